@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/fir1/news/config"
+	grpc_api "github.com/fir1/news/grpc"
 	http_rest "github.com/fir1/news/http"
 	newsSvc "github.com/fir1/news/internal/news/service"
 	"go.uber.org/fx"
@@ -14,20 +16,45 @@ import (
 )
 
 func main() {
-	err := fx.New(
+	var restServer *http_rest.Service
+	var grpcServer *grpc_api.Service
+	app := fx.New(
 		fx.Options(
 			config.FxProvide,
 			newsSvc.FxProvide,
 			http_rest.FxProvide,
+			grpc_api.FxProvide,
 		),
-		fx.Invoke(run),
-	).Err()
+		fx.Invoke(http_rest.RegisterCacheWarmer),
+		fx.Populate(&restServer, &grpcServer),
+	)
+	err := app.Err()
 	if err != nil {
 		log.Panic(err)
 	}
+
+	// the lifecycle hooks, e.g. the cache warmer, run before the server starts listening
+	startCtx, cancelStart := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancelStart()
+	err = app.Start(startCtx)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	err = run(restServer, grpcServer)
+
+	stopCtx, cancelStop := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancelStop()
+	stopErr := app.Stop(stopCtx)
+	if err != nil {
+		log.Panic(err)
+	}
+	if stopErr != nil {
+		log.Panic(stopErr)
+	}
 }
 
-func run(restServer *http_rest.Service) error {
+func run(restServer *http_rest.Service, grpcServer *grpc_api.Service) error {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
 
@@ -35,29 +62,39 @@ func run(restServer *http_rest.Service) error {
 
 	var wg sync.WaitGroup
 
-	wg.Add(1)
+	wg.Add(2)
 
 	stop := make(chan struct{})
-	errChan := make(chan error)
+	// buffered for both servers, the second one may fail while the first one stops
+	errChan := make(chan error, 2)
 
 	go func() {
 		defer wg.Done()
 
 		err := restServer.Run(stop)
 		if err != nil {
-			errChan <- err
+			errChan <- fmt.Errorf("webhook rest api http is down (error: %w)", err)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		err := grpcServer.Run(stop)
+		if err != nil {
+			errChan <- fmt.Errorf("grpc api is down (error: %w)", err)
 		}
 	}()
 
-	// Wait signal or error from services
+	// Wait signal or error from services, either one stops all of them
+	var err error
 	select {
 	case <-interrupt:
-	case err := <-errChan:
-		return fmt.Errorf("webhook rest api http is down (error: %w)", err)
+	case err = <-errChan:
 	}
 
-	stop <- struct{}{}
+	close(stop)
 	wg.Wait()
 
-	return nil
+	return err
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
+	"time"
 )
 
 func NewParsedConfig() (Config, error) {
@@ -17,4 +18,86 @@ type Config struct {
 	ServerHostName       string `envconfig:"SERVER_HOST_NAME" default:"http://0.0.0.0"`
 	Port                 int    `envconfig:"PORT" default:"8080"`
 	LoadBalancerHostPort int    `envconfig:"LOAD_BALANCER_HOST_PORT" default:"8080"`
+	// port of the gRPC API, served next to the REST API
+	GRPCPort int `envconfig:"GRPC_PORT" default:"9090"`
+	// how often WatchNews checks the feeds for news published since the last check
+	GRPCWatchInterval time.Duration `envconfig:"GRPC_WATCH_INTERVAL" default:"1m"`
+	// OutboundTimeout limits a whole request to a feed or article including reading the body
+	OutboundTimeout               time.Duration `envconfig:"OUTBOUND_TIMEOUT" default:"1m"`
+	OutboundDialTimeout           time.Duration `envconfig:"OUTBOUND_DIAL_TIMEOUT" default:"10s"`
+	OutboundTLSHandshakeTimeout   time.Duration `envconfig:"OUTBOUND_TLS_HANDSHAKE_TIMEOUT" default:"10s"`
+	OutboundResponseHeaderTimeout time.Duration `envconfig:"OUTBOUND_RESPONSE_HEADER_TIMEOUT" default:"30s"`
+	OutboundIdleConnTimeout       time.Duration `envconfig:"OUTBOUND_IDLE_CONN_TIMEOUT" default:"90s"`
+	OutboundMaxIdleConns          int           `envconfig:"OUTBOUND_MAX_IDLE_CONNS" default:"100"`
+	OutboundMaxIdleConnsPerHost   int           `envconfig:"OUTBOUND_MAX_IDLE_CONNS_PER_HOST" default:"10"`
+	// 0 means no limit of connections per host
+	OutboundMaxConnsPerHost int `envconfig:"OUTBOUND_MAX_CONNS_PER_HOST" default:"0"`
+	// maximum size of a decoded feed or article response, 10 MiB by default
+	OutboundMaxResponseBytes int64  `envconfig:"OUTBOUND_MAX_RESPONSE_BYTES" default:"10485760"`
+	OutboundUserAgent        string `envconfig:"OUTBOUND_USER_AGENT" default:"NewsFeedBot/1.0 (+https://github.com/fir1/news)"`
+	// politeness towards every upstream host: token bucket rate, its burst and the
+	// number of requests in flight, requests queue until their turn or their deadline
+	OutboundRateLimitPerHost     float64 `envconfig:"OUTBOUND_RATE_LIMIT_PER_HOST" default:"5"`
+	OutboundRateLimitBurst       int     `envconfig:"OUTBOUND_RATE_LIMIT_BURST" default:"10"`
+	OutboundMaxConcurrentPerHost int     `envconfig:"OUTBOUND_MAX_CONCURRENT_PER_HOST" default:"4"`
+	// a feed source failing CIRCUIT_BREAKER_FAILURE_THRESHOLD times in a row is skipped for
+	// CIRCUIT_BREAKER_OPEN_TIMEOUT, then up to CIRCUIT_BREAKER_HALF_OPEN_MAX_REQUESTS probes are let through
+	CircuitBreakerFailureThreshold    int           `envconfig:"CIRCUIT_BREAKER_FAILURE_THRESHOLD" default:"5"`
+	CircuitBreakerOpenTimeout         time.Duration `envconfig:"CIRCUIT_BREAKER_OPEN_TIMEOUT" default:"30s"`
+	CircuitBreakerHalfOpenMaxRequests int           `envconfig:"CIRCUIT_BREAKER_HALF_OPEN_MAX_REQUESTS" default:"1"`
+	// a failing feed is fetched up to FEED_RETRY_ATTEMPTS times with an exponential back off
+	// from FEED_RETRY_BASE_DELAY up to FEED_RETRY_MAX_DELAY, a Retry-After longer than
+	// FEED_RETRY_MAX_RETRY_AFTER is not waited for, 0 FEED_RETRY_BUDGET means only the
+	// request deadline bounds the total time spent on retries
+	FeedRetryAttempts      uint          `envconfig:"FEED_RETRY_ATTEMPTS" default:"3"`
+	FeedRetryBaseDelay     time.Duration `envconfig:"FEED_RETRY_BASE_DELAY" default:"200ms"`
+	FeedRetryMaxDelay      time.Duration `envconfig:"FEED_RETRY_MAX_DELAY" default:"5s"`
+	FeedRetryMaxRetryAfter time.Duration `envconfig:"FEED_RETRY_MAX_RETRY_AFTER" default:"10s"`
+	FeedRetryBudget        time.Duration `envconfig:"FEED_RETRY_BUDGET" default:"0s"`
+	// one-of: memory - every replica keeps its own cache, redis - the replicas share one cache,
+	// disk - the cache is kept in CACHE_DISK_PATH and survives a restart
+	CacheBackend string        `envconfig:"CACHE_BACKEND" default:"memory"`
+	CacheTTL     time.Duration `envconfig:"CACHE_TTL" default:"5m"`
+	// lifetime of cached news lists and of cached articles, which change far less often
+	CacheNewsTTL    time.Duration `envconfig:"CACHE_NEWS_TTL" default:"5m"`
+	CacheArticleTTL time.Duration `envconfig:"CACHE_ARTICLE_TTL" default:"1h"`
+	// news lists and articles past their TTL are served for up to CACHE_MAX_STALE while
+	// they are refreshed in the background, also when the refresh fails
+	CacheMaxStale time.Duration `envconfig:"CACHE_MAX_STALE" default:"1h"`
+	CacheDiskPath string        `envconfig:"CACHE_DISK_PATH" default:"data/news-cache.db"`
+	// entries of at least CACHE_COMPRESSION_THRESHOLD bytes are stored gzipped, so more
+	// news lists fit in memory and are sent to clients accepting gzip as they are
+	CacheCompressionEnabled   bool   `envconfig:"CACHE_COMPRESSION_ENABLED" default:"true"`
+	CacheCompressionThreshold int    `envconfig:"CACHE_COMPRESSION_THRESHOLD" default:"1024"`
+	RedisAddr                 string `envconfig:"REDIS_ADDR" default:"localhost:6379"`
+	RedisPassword             string `envconfig:"REDIS_PASSWORD"`
+	RedisDB                   int    `envconfig:"REDIS_DB" default:"0"`
+	// every cache key is prefixed so the redis server can be shared with other applications
+	RedisKeyPrefix string `envconfig:"REDIS_KEY_PREFIX" default:"news:"`
+	// keeps hot entries of a shared cache backend in memory for up to CACHE_L1_TTL, writes
+	// are fanned out to the other replicas so they drop their outdated copy
+	CacheL1Enabled bool          `envconfig:"CACHE_L1_ENABLED" default:"false"`
+	CacheL1TTL     time.Duration `envconfig:"CACHE_L1_TTL" default:"1m"`
+	// the cache warmer refreshes the news lists of CACHE_WARM_NEWS_QUERIES, the CACHE_WARM_TOP_NEWS
+	// most requested lists and the CACHE_WARM_TOP_ARTICLES most requested articles, topped up with
+	// the latest articles of the warmed lists, at startup and every CACHE_WARM_INTERVAL
+	CacheWarmEnabled  bool          `envconfig:"CACHE_WARM_ENABLED" default:"true"`
+	CacheWarmInterval time.Duration `envconfig:"CACHE_WARM_INTERVAL" default:"4m"`
+	// bounds the warm up at startup, the server starts listening once it is done
+	CacheWarmStartupTimeout time.Duration `envconfig:"CACHE_WARM_STARTUP_TIMEOUT" default:"10s"`
+	// comma separated query strings of /news, e.g. `providers=bbc&categories=technology`
+	CacheWarmNewsQueries []string `envconfig:"CACHE_WARM_NEWS_QUERIES" default:"categories=general"`
+	CacheWarmTopNews     int      `envconfig:"CACHE_WARM_TOP_NEWS" default:"5"`
+	CacheWarmTopArticles int      `envconfig:"CACHE_WARM_TOP_ARTICLES" default:"10"`
+	// responses of at least RESPONSE_COMPRESSION_MIN_SIZE bytes are compressed with brotli,
+	// zstd or gzip, whichever the client prefers
+	ResponseCompressionEnabled bool `envconfig:"RESPONSE_COMPRESSION_ENABLED" default:"true"`
+	ResponseCompressionMinSize int  `envconfig:"RESPONSE_COMPRESSION_MIN_SIZE" default:"1024"`
+	// bearer token required by the /admin endpoints, they are disabled while it is empty
+	AdminToken string `envconfig:"ADMIN_TOKEN"`
+	// comma separated host names (`example.com`, `*.example.com`), IPs or CIDR ranges
+	// the server may always fetch from, even when they resolve to internal addresses
+	OutboundAllowedHosts []string `envconfig:"OUTBOUND_ALLOWED_HOSTS"`
+	// comma separated host names, IPs or CIDR ranges the server must never fetch from
+	OutboundDeniedHosts []string `envconfig:"OUTBOUND_DENIED_HOSTS"`
 }
@@ -20,9 +20,565 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/cache/entries": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a single entry by its key, e.g. ` + "`" + `article:https://www.bbc.co.uk/news/uk-1` + "`" + `, or every entry whose key starts with the prefix, e.g. ` + "`" + `article:https://www.bbc.co.uk/` + "`" + ` for all articles of a provider or ` + "`" + `news:` + "`" + ` for all news lists",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete cache entries",
+                "operationId": "admin-cache-entries-delete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key of the entry to delete",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "prefix of the keys to delete",
+                        "name": "prefix",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DeleteCacheEntriesResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cache/reset": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete every entry of the cache, the next requests fetch the news and articles from their sources again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Reset the cache",
+                "operationId": "admin-cache-reset",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cache/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the hits, misses and evictions since the server started together with the number of entries and their size",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the cache statistics",
+                "operationId": "admin-cache-stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CacheStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/article": {
             "get": {
-                "description": "Get article, it shows a single news article on screen, using an HTML display",
+                "description": "Get article, it shows a single news article on screen, using an HTML display.\nClients sending ` + "`" + `Accept: application/json` + "`" + ` receive the same payload as ` + "`" + `GET /articles` + "`" + `,\nthe ` + "`" + `format` + "`" + ` query param returns the article as Markdown or plain text instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/html",
+                    "application/json",
+                    "text/plain",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "summary": "Get article, it shows a single news article on screen, using an HTML display",
+                "operationId": "article-get",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "one-of: html, markdown, text. By default the article is shown as an HTML page,\n` + "`" + `markdown` + "`" + ` returns CommonMark and ` + "`" + `text` + "`" + ` returns plain text wrapped at 80 columns.",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "max-age is the time the response stays fresh in the cache"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "strong validator of the response body"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles": {
+            "get": {
+                "description": "Get article as JSON, it returns the extracted article together with its metadata, word count and reading time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "summary": "Get article as JSON",
+                "operationId": "articles-get",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "one-of: html, markdown, text. By default the article is shown as an HTML page,\n` + "`" + `markdown` + "`" + ` returns CommonMark and ` + "`" + `text` + "`" + ` returns plain text wrapped at 80 columns.",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "url",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ArticleResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "max-age is the time the response stays fresh in the cache"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "strong validator of the response body"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/diagnostics/circuit-breakers": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the state of the circuit breaker of every news feed source fetched since the server started",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Diagnostics"
+                ],
+                "summary": "List the circuit breakers of the news feed sources",
+                "operationId": "diagnostics-circuit-breakers-list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListCircuitBreakersResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "description": "Run a GraphQL query against the schema in http/schema.graphql, e.g. a page of news together with the extracted article of the first one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "summary": "Query news and articles with GraphQL",
+                "operationId": "graphql-query",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/GraphQLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GraphQLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Get health of server",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health-Server"
+                ],
+                "summary": "Get health of server",
+                "operationId": "get-health",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/news": {
+            "get": {
+                "description": "List news articles from a public news feed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "News"
+                ],
+                "summary": "List news articles from a public news feed",
+                "operationId": "news-list",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "possible values: \"general, technology\". By default we will take news feed with category ` + "`" + `general` + "`" + `.",
+                        "name": "categories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "if value ` + "`" + `news_source_url` + "`" + ` filled the system will try to fetch news from the given ` + "`" + `url` + "`" + `.\nThe url must be a valid RSS url link ending with ` + "`" + `.xml` + "`" + `\nand please don't fill anything for ` + "`" + `providers` + "`" + ` field because you are allowed\nto choose to get a news feed either via choosing existing providers or by giving news_source_url",
+                        "name": "news_source_url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "possible values: \"sky, bbc\". By default we will take news feed from all the available providers.\nif value ` + "`" + `providers` + "`" + ` filled the system will try to fetch news from the given providers\nand please don't fill anything for ` + "`" + `news_source_url` + "`" + ` field because you are allowed\nto choose to get a news feed either via choosing existing providers or by giving news_source_url.",
+                        "name": "providers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "DESC",
+                        "description": "one-of: DESC - latest article will be shown first in the list, ASC - oldest article will be shown first in the list",
+                        "name": "sort_by_publish_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ListNewsResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "max-age is the time the response stays fresh in the cache"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "strong validator of the response body"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/articles": {
+            "get": {
+                "description": "Get the extracted article together with its metadata, word count and reading time",
                 "consumes": [
                     "application/json"
                 ],
@@ -32,9 +588,15 @@ const docTemplate = `{
                 "tags": [
                     "News"
                 ],
-                "summary": "Get article, it shows a single news article on screen, using an HTML display",
-                "operationId": "article-get",
+                "summary": "Get article as JSON",
+                "operationId": "v1-articles-get",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "one-of: html, markdown, text. By default the article is shown as an HTML page,\n` + "`" + `markdown` + "`" + ` returns CommonMark and ` + "`" + `text` + "`" + ` returns plain text wrapped at 80 columns.",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "name": "url",
@@ -43,20 +605,77 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ArticleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "max-age is the time the response stays fresh in the cache"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "strong validator of the response body"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
                     },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
                     }
                 }
             }
         },
-        "/health": {
+        "/v1/diagnostics/circuit-breakers": {
             "get": {
-                "description": "Get health of server",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the state of the circuit breaker of every news feed source fetched since the server started",
                 "consumes": [
                     "application/json"
                 ],
@@ -64,13 +683,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Health-Server"
+                    "Diagnostics"
                 ],
-                "summary": "Get health of server",
-                "operationId": "get-health",
+                "summary": "List the circuit breakers of the news feed sources",
+                "operationId": "v1-diagnostics-circuit-breakers-list",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/CircuitBreaker"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -78,9 +727,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/news": {
+        "/v1/news": {
             "get": {
-                "description": "List news articles from a public news feed",
+                "description": "List a page of the news articles, the meta data holds the pagination and the outcome of every feed",
                 "consumes": [
                     "application/json"
                 ],
@@ -91,7 +740,7 @@ const docTemplate = `{
                     "News"
                 ],
                 "summary": "List news articles from a public news feed",
-                "operationId": "news-list",
+                "operationId": "v1-news-list",
                 "parameters": [
                     {
                         "type": "array",
@@ -125,26 +774,268 @@ const docTemplate = `{
                         "description": "one-of: DESC - latest article will be shown first in the list, ASC - oldest article will be shown first in the list",
                         "name": "sort_by_publish_date",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page of the news starting at 1, 1 by default",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "news per page up to 100, 20 by default",
+                        "name": "per_page",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ListNewsResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/News"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/NewsMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "max-age is the time the response stays fresh in the cache"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "strong validator of the response body"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
+                    },
                     "400": {
-                        "description": "Bad Request"
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/APIResponse"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "APIError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "one-of: invalid_argument, not_found, upstream_unavailable, upstream_rate_limited, timeout,\ncanceled, internal",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "APIResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/APIError"
+                    }
+                },
+                "meta": {}
+            }
+        },
+        "ArticleResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "canonical_url": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "link": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "reading_time_minutes": {
+                    "description": "estimated reading time of the content in minutes",
+                    "type": "integer"
+                },
+                "section": {
+                    "type": "string"
+                },
+                "site_name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "word_count": {
+                    "description": "number of words in the extracted content",
+                    "type": "integer"
+                }
+            }
+        },
+        "CacheStats": {
+            "type": "object",
+            "properties": {
+                "backend": {
+                    "description": "one-of: memory, redis, disk, two-tier",
+                    "type": "string"
+                },
+                "bytes": {
+                    "description": "bytes of the keys and values of the memory backend, size of the file of the disk backend",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "evictions": {
+                    "type": "integer"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "tiers": {
+                    "description": "stats of the in memory L1 and the shared L2 of a two-tier cache",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CacheStats"
+                    }
+                }
+            }
+        },
+        "CircuitBreaker": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_failure_at": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "canonical URL of the feed guarded by the circuit breaker",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "one-of: closed, open, half-open",
+                    "type": "string"
+                }
+            }
+        },
+        "DeleteCacheEntriesResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "GraphQLRequest": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "GraphQLResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "ListCircuitBreakersResponse": {
+            "type": "object",
+            "properties": {
+                "circuit_breakers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CircuitBreaker"
+                    }
+                }
+            }
+        },
         "ListNewsResponse": {
             "type": "object",
             "properties": {
@@ -153,6 +1044,13 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/News"
                     }
+                },
+                "sources": {
+                    "description": "the outcome of every feed the news were collected from",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NewsSource"
+                    }
                 }
             }
         },
@@ -178,6 +1076,90 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "NewsMeta": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/Pagination"
+                },
+                "sources": {
+                    "description": "the outcome of every feed the news were collected from",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NewsSource"
+                    }
+                }
+            }
+        },
+        "NewsSource": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "error_kind": {
+                    "description": "one-of: not_found, gone, rate_limited, client_error, server_error, unexpected_status, invalid_feed - set when the feed itself failed",
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "one-of: ok, failed, skipped - the circuit breaker of the source is open and it was not fetched",
+                    "type": "string"
+                },
+                "upstream_status": {
+                    "description": "the HTTP status code the feed responded with when it failed",
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "Pagination": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "per_page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "number of news of all pages",
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "instance": {
+                    "description": "path of the request the problem occurred in",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "URI reference identifying the problem type, one-of: /problems/invalid_argument,\n/problems/not_found, /problems/upstream_unavailable, /problems/upstream_rate_limited,\n/problems/timeout, /problems/canceled, /problems/internal or about:blank",
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
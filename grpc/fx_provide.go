@@ -0,0 +1,7 @@
+package grpc
+
+import "go.uber.org/fx"
+
+var FxProvide = fx.Provide(
+	NewService,
+)
@@ -0,0 +1,196 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	newsv1 "github.com/fir1/news/proto/news/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+func (s *Service) ListNews(ctx context.Context, request *newsv1.ListNewsRequest) (*newsv1.ListNewsResponse, error) {
+	params := listNewsParams(request.GetProviders(), request.GetCategories(), request.GetNewsSourceUrl())
+	switch request.GetSort() {
+	case newsv1.Sort_SORT_ASC:
+		params.SortByPublishDate = newsSvc.SortASC
+	case newsv1.Sort_SORT_DESC:
+		params.SortByPublishDate = newsSvc.SortDESC
+	}
+
+	response, err := s.newsService.ListNews(ctx, params)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	news := make([]*newsv1.News, len(response.NewsFeeds))
+	for i, feed := range response.NewsFeeds {
+		news[i] = serializeNewsToProto(feed)
+	}
+	sources := make([]*newsv1.NewsSource, len(response.Sources))
+	for i, source := range response.Sources {
+		sources[i] = serializeNewsSourceToProto(source)
+	}
+	return &newsv1.ListNewsResponse{News: news, Sources: sources}, nil
+}
+
+func (s *Service) GetArticle(ctx context.Context, request *newsv1.GetArticleRequest) (*newsv1.GetArticleResponse, error) {
+	article, err := s.newsService.GetArticle(ctx, request.GetUrl())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &newsv1.GetArticleResponse{Article: serializeArticleToProto(article)}, nil
+}
+
+// WatchNews polls the feeds every GRPC_WATCH_INTERVAL and sends the news it did not send
+// before, a failing poll is retried with the next one once the first news were sent.
+func (s *Service) WatchNews(request *newsv1.WatchNewsRequest, stream newsv1.NewsService_WatchNewsServer) error {
+	ctx := stream.Context()
+	params := listNewsParams(request.GetProviders(), request.GetCategories(), request.GetNewsSourceUrl())
+	params.SortByPublishDate = newsSvc.SortASC
+
+	ticker := time.NewTicker(s.config.GRPCWatchInterval)
+	defer ticker.Stop()
+
+	var sent map[string]bool
+	for {
+		response, err := s.newsService.ListNews(ctx, params)
+		switch {
+		case err != nil && sent == nil:
+			return statusError(err)
+		case err != nil:
+			s.logger.Warnf("watch news: %v", err)
+		default:
+			latest := make(map[string]bool, len(response.NewsFeeds))
+			for _, feed := range response.NewsFeeds {
+				latest[feed.Link] = true
+				if sent[feed.Link] {
+					continue
+				}
+				err = stream.Send(&newsv1.WatchNewsResponse{News: serializeNewsToProto(feed)})
+				if err != nil {
+					return err
+				}
+			}
+
+			// the news of a source which failed or was skipped are missing from the poll,
+			// they are kept until every source answered again so they are not sent twice
+			if sent != nil && !allSourcesOK(response.Sources) {
+				for link := range sent {
+					latest[link] = true
+				}
+			}
+			sent = latest
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.shutdown:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+func allSourcesOK(sources []newsSvc.SourceStatus) bool {
+	for _, source := range sources {
+		if source.State != newsSvc.SourceStateOK {
+			return false
+		}
+	}
+	return true
+}
+
+// statusError reports an error of the news service with the gRPC code of its ErrorCode.
+func statusError(err error) error {
+	code := codes.Unavailable
+	switch newsSvc.Code(err) {
+	case newsSvc.CodeInvalidArgument:
+		code = codes.InvalidArgument
+	case newsSvc.CodeNotFound:
+		code = codes.NotFound
+	case newsSvc.CodeUpstreamRateLimited:
+		code = codes.ResourceExhausted
+	case newsSvc.CodeTimeout:
+		code = codes.DeadlineExceeded
+	case newsSvc.CodeCanceled:
+		code = codes.Canceled
+	case newsSvc.CodeInternal:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
+
+func listNewsParams(providers, categories []string, newsSourceURL string) newsSvc.ListNewsParams {
+	params := newsSvc.ListNewsParams{}
+	if len(providers) > 0 {
+		prs := make([]model.NewsProvider, len(providers))
+		for i, provider := range providers {
+			prs[i] = model.NewsProvider(provider)
+		}
+		params.Providers = &prs
+	}
+	if len(categories) > 0 {
+		params.Categories = &categories
+	}
+	if newsSourceURL != "" {
+		params.NewsSourceURL = &newsSourceURL
+	}
+	return params
+}
+
+func serializeNewsToProto(feed model.NewsFeed) *newsv1.News {
+	return &newsv1.News{
+		Title:           feed.Title,
+		Description:     feed.Description,
+		Link:            feed.Link,
+		PublishDate:     timestamppb.New(feed.PublishDate),
+		Provider:        string(feed.Provider),
+		ProviderLogoUrl: feed.ProviderLogoURL,
+	}
+}
+
+func serializeNewsSourceToProto(source newsSvc.SourceStatus) *newsv1.NewsSource {
+	result := &newsv1.NewsSource{
+		Url:      source.URL,
+		Provider: string(source.Provider),
+		Category: source.Category,
+		Status:   string(source.State),
+	}
+	if source.Err != nil {
+		result.Error = source.Err.Error()
+	}
+
+	var upstreamErr *newsSvc.UpstreamError
+	if errors.As(source.Err, &upstreamErr) {
+		result.ErrorKind = string(upstreamErr.Kind)
+		result.UpstreamStatus = int32(upstreamErr.StatusCode)
+	}
+	return result
+}
+
+func serializeArticleToProto(article model.Article) *newsv1.Article {
+	result := &newsv1.Article{
+		Title:              article.Title,
+		Description:        article.Description,
+		Content:            article.Content,
+		ContentHtml:        article.ContentHTML,
+		Link:               article.Link,
+		CanonicalUrl:       article.CanonicalURL,
+		ImageUrl:           article.ImageURL,
+		SiteName:           article.SiteName,
+		Author:             article.Author,
+		Section:            article.Section,
+		Keywords:           article.Keywords,
+		WordCount:          int32(article.WordCount),
+		ReadingTimeMinutes: int32(article.ReadingTimeMinutes),
+	}
+	if !article.PublishedAt.IsZero() {
+		result.PublishedAt = timestamppb.New(article.PublishedAt)
+	}
+	return result
+}
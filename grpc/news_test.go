@@ -0,0 +1,233 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	newsv1 "github.com/fir1/news/proto/news/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// MockNewsService is a mock implementation of the news service
+type MockNewsService struct {
+	mock.Mock
+}
+
+func (m *MockNewsService) GetArticle(ctx context.Context, articleURL string) (model.Article, error) {
+	args := m.Called(articleURL)
+	return args.Get(0).(model.Article), args.Error(1)
+}
+
+func (m *MockNewsService) ListNews(ctx context.Context, params newsSvc.ListNewsParams) (newsSvc.ListNewsResponse, error) {
+	args := m.Called(params)
+	return args.Get(0).(newsSvc.ListNewsResponse), args.Error(1)
+}
+
+func newService(t *testing.T, news newsSvc.NewsInterface, watchInterval time.Duration) *Service {
+	s, err := NewService(logrus.New(), news, config.Config{GRPCWatchInterval: watchInterval})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// newClient serves s over an in memory listener.
+func newClient(t *testing.T, s *Service) newsv1.NewsServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	newsv1.RegisterNewsServiceServer(server, s)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return newsv1.NewNewsServiceClient(conn)
+}
+
+func TestListNews(t *testing.T) {
+	published := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+	news := new(MockNewsService)
+	news.On("ListNews", newsSvc.ListNewsParams{
+		Providers:         &[]model.NewsProvider{model.NewsProviderBBC},
+		Categories:        &[]string{"technology"},
+		SortByPublishDate: newsSvc.SortASC,
+	}).Return(newsSvc.ListNewsResponse{
+		NewsFeeds: []model.NewsFeed{{Title: "News", Link: "https://www.bbc.co.uk/news/1", PublishDate: published, Provider: model.NewsProviderBBC}},
+		Sources: []newsSvc.SourceStatus{{
+			URL:      "http://feeds.bbci.co.uk/news/technology/rss.xml",
+			Provider: model.NewsProviderBBC,
+			Category: "technology",
+			State:    newsSvc.SourceStateOK,
+		}},
+	}, nil)
+	client := newClient(t, newService(t, news, time.Minute))
+
+	response, err := client.ListNews(context.Background(), &newsv1.ListNewsRequest{
+		Providers:  []string{"bbc"},
+		Categories: []string{"technology"},
+		Sort:       newsv1.Sort_SORT_ASC,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, response.GetNews(), 1)
+	assert.Equal(t, "News", response.GetNews()[0].GetTitle())
+	assert.Equal(t, published, response.GetNews()[0].GetPublishDate().AsTime())
+	assert.Len(t, response.GetSources(), 1)
+	assert.Equal(t, "ok", response.GetSources()[0].GetStatus())
+}
+
+func TestGetArticle(t *testing.T) {
+	news := new(MockNewsService)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/1").Return(model.Article{
+		Title:     "News",
+		Content:   "The extracted content",
+		WordCount: 3,
+	}, nil)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/missing").Return(model.Article{}, &newsSvc.UpstreamError{
+		Kind:       newsSvc.UpstreamNotFound,
+		URL:        "https://www.bbc.co.uk/news/missing",
+		StatusCode: http.StatusNotFound,
+	})
+	news.On("GetArticle", "https://www.bbc.co.uk/news/busy").Return(model.Article{}, &newsSvc.UpstreamError{
+		Kind:       newsSvc.UpstreamRateLimited,
+		URL:        "https://www.bbc.co.uk/news/busy",
+		StatusCode: http.StatusTooManyRequests,
+	})
+	news.On("GetArticle", "http://127.0.0.1/admin").Return(model.Article{}, newsSvc.ErrArgument{Err: io.EOF})
+	news.On("GetArticle", "https://www.bbc.co.uk/news/bug").Return(model.Article{}, errors.New("unexpected failure"))
+	client := newClient(t, newService(t, news, time.Minute))
+
+	testCases := []struct {
+		name  string
+		url   string
+		code  codes.Code
+		title string
+	}{
+		{name: "OK", url: "https://www.bbc.co.uk/news/1", code: codes.OK, title: "News"},
+		{name: "NotFound", url: "https://www.bbc.co.uk/news/missing", code: codes.NotFound},
+		{name: "RateLimited", url: "https://www.bbc.co.uk/news/busy", code: codes.ResourceExhausted},
+		{name: "InvalidArgument", url: "http://127.0.0.1/admin", code: codes.InvalidArgument},
+		{name: "Internal", url: "https://www.bbc.co.uk/news/bug", code: codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := client.GetArticle(context.Background(), &newsv1.GetArticleRequest{Url: tc.url})
+			assert.Equal(t, tc.code, status.Code(err))
+			assert.Equal(t, tc.title, response.GetArticle().GetTitle())
+		})
+	}
+}
+
+func TestWatchNews(t *testing.T) {
+	first := model.NewsFeed{Title: "First", Link: "https://www.bbc.co.uk/news/1"}
+	second := model.NewsFeed{Title: "Second", Link: "https://www.bbc.co.uk/news/2"}
+	third := model.NewsFeed{Title: "Third", Link: "https://www.bbc.co.uk/news/3"}
+
+	params := newsSvc.ListNewsParams{SortByPublishDate: newsSvc.SortASC}
+	news := new(MockNewsService)
+	news.On("ListNews", params).Return(newsSvc.ListNewsResponse{NewsFeeds: []model.NewsFeed{first, second}}, nil).Once()
+	news.On("ListNews", params).Return(newsSvc.ListNewsResponse{}, &newsSvc.UpstreamError{Kind: newsSvc.UpstreamServerError}).Once()
+	news.On("ListNews", params).Return(newsSvc.ListNewsResponse{NewsFeeds: []model.NewsFeed{first, second, third}}, nil)
+
+	client := newClient(t, newService(t, news, 10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchNews(ctx, &newsv1.WatchNewsRequest{})
+	assert.NoError(t, err)
+
+	// a failing poll in between does not end the stream nor send the news again
+	var titles []string
+	for len(titles) < 3 {
+		response, err := stream.Recv()
+		if !assert.NoError(t, err) {
+			return
+		}
+		titles = append(titles, response.GetNews().GetTitle())
+	}
+	assert.Equal(t, []string{"First", "Second", "Third"}, titles)
+}
+
+func TestWatchNewsFailsWithoutNews(t *testing.T) {
+	news := new(MockNewsService)
+	news.On("ListNews", mock.Anything).Return(newsSvc.ListNewsResponse{}, newsSvc.ErrArgument{Err: io.EOF})
+	client := newClient(t, newService(t, news, time.Minute))
+
+	stream, err := client.WatchNews(context.Background(), &newsv1.WatchNewsRequest{Providers: []string{"cnn"}})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchNewsKeepsNewsOfFailedSources(t *testing.T) {
+	bbc := model.NewsFeed{Title: "BBC", Link: "https://www.bbc.co.uk/news/1"}
+	sky := model.NewsFeed{Title: "Sky", Link: "https://news.sky.com/story/1"}
+	latest := model.NewsFeed{Title: "Latest", Link: "https://www.bbc.co.uk/news/2"}
+	bbcSource := newsSvc.SourceStatus{URL: "http://feeds.bbci.co.uk/news/uk/rss.xml", State: newsSvc.SourceStateOK}
+	skySource := newsSvc.SourceStatus{URL: "http://feeds.skynews.com/feeds/rss/uk.xml", State: newsSvc.SourceStateOK}
+	skyFailed := skySource
+	skyFailed.State = newsSvc.SourceStateFailed
+
+	params := newsSvc.ListNewsParams{SortByPublishDate: newsSvc.SortASC}
+	news := new(MockNewsService)
+	news.On("ListNews", params).Return(newsSvc.ListNewsResponse{
+		NewsFeeds: []model.NewsFeed{bbc, sky},
+		Sources:   []newsSvc.SourceStatus{bbcSource, skySource},
+	}, nil).Once()
+	// sky fails for one poll and recovers with the next one
+	news.On("ListNews", params).Return(newsSvc.ListNewsResponse{
+		NewsFeeds: []model.NewsFeed{bbc},
+		Sources:   []newsSvc.SourceStatus{bbcSource, skyFailed},
+	}, nil).Once()
+	news.On("ListNews", params).Return(newsSvc.ListNewsResponse{
+		NewsFeeds: []model.NewsFeed{bbc, sky, latest},
+		Sources:   []newsSvc.SourceStatus{bbcSource, skySource},
+	}, nil)
+
+	client := newClient(t, newService(t, news, 10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchNews(ctx, &newsv1.WatchNewsRequest{})
+	assert.NoError(t, err)
+
+	// the news of sky are not sent again once it recovered
+	var titles []string
+	for len(titles) < 3 {
+		response, err := stream.Recv()
+		if !assert.NoError(t, err) {
+			return
+		}
+		titles = append(titles, response.GetNews().GetTitle())
+	}
+	assert.Equal(t, []string{"BBC", "Sky", "Latest"}, titles)
+}
+
+func TestNewServiceRequiresWatchInterval(t *testing.T) {
+	_, err := NewService(logrus.New(), new(MockNewsService), config.Config{})
+	assert.EqualError(t, err, "GRPC_WATCH_INTERVAL: 0s is invalid must be greater than zero")
+}
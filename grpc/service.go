@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"fmt"
+	"github.com/fir1/news/config"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	newsv1 "github.com/fir1/news/proto/news/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"net"
+	"sync"
+)
+
+type Service struct {
+	newsv1.UnimplementedNewsServiceServer
+	logger      *logrus.Logger
+	newsService newsSvc.NewsInterface
+	config      config.Config
+	// closed when the server stops, WatchNews streams would keep a graceful stop waiting
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+func NewService(logger *logrus.Logger, newsSvc newsSvc.NewsInterface, cnf config.Config) (*Service, error) {
+	// the ticker of WatchNews panics without a positive interval
+	if cnf.GRPCWatchInterval <= 0 {
+		return nil, fmt.Errorf("GRPC_WATCH_INTERVAL: %v is invalid must be greater than zero", cnf.GRPCWatchInterval)
+	}
+
+	return &Service{
+		logger:      logger,
+		newsService: newsSvc,
+		config:      cnf,
+		shutdown:    make(chan struct{}),
+	}, nil
+}
+
+// Run serves the gRPC API on GRPC_PORT until stop receives or is closed.
+func (s *Service) Run(stop chan struct{}) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.GRPCPort))
+	if err != nil {
+		return fmt.Errorf("error: starting gRPC API: %w", err)
+	}
+
+	server := grpc.NewServer()
+	newsv1.RegisterNewsServiceServer(server, s)
+	// lets tools like grpcurl discover the services
+	reflection.Register(server)
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		s.logger.Printf("gRPC API listening on port: %d for environment: %s", s.config.GRPCPort, s.config.Environment)
+		serverErrors <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serverErrors:
+		return fmt.Errorf("error: serving gRPC API: %w", err)
+	case <-stop:
+		s.logger.Warn("grpc receive STOP signal")
+		s.shutdownOnce.Do(func() { close(s.shutdown) })
+		server.GracefulStop()
+		s.logger.Info("grpc was shut down gracefully")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"fmt"
+	"github.com/fir1/news/internal/news/model"
+	"github.com/fir1/news/pkg/htmlconv"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	articleFormatHTML     = "html"
+	articleFormatMarkdown = "markdown"
+	articleFormatText     = "text"
+)
+
+func (s *Service) writeArticle(w http.ResponseWriter, r *http.Request, article model.Article,
+	render func(model.Article) (string, error), contentType string,
+) {
+	body, err := render(article)
+	if err != nil {
+		s.respondProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte(body))
+	if err != nil {
+		s.logger.Errorf("article write error: %v", err)
+	}
+}
+
+// renderArticleMarkdown renders the article as a CommonMark document with the
+// title as heading, followed by the byline, description, body and source link.
+func renderArticleMarkdown(article model.Article) (string, error) {
+	base, _ := url.Parse(article.Link)
+	content, err := htmlconv.ToMarkdown(article.ContentHTML, base)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if article.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", htmlconv.EscapeMarkdown(collapseSpace(article.Title)))
+	}
+	if byline := articleByline(article); byline != "" {
+		fmt.Fprintf(&b, "*%s*\n\n", htmlconv.EscapeMarkdown(byline))
+	}
+	if article.Description != "" {
+		fmt.Fprintf(&b, "> %s\n\n", htmlconv.EscapeMarkdown(collapseSpace(article.Description)))
+	}
+	if content != "" {
+		fmt.Fprintf(&b, "%s\n\n", content)
+	}
+	fmt.Fprintf(&b, "---\n\nSource: <%s>\n", autolinkEscaper.Replace(article.SourceURL()))
+	return b.String(), nil
+}
+
+// renderArticleText renders the article as plain text suitable for text-to-speech
+// and terminals, paragraphs are separated by blank lines and wrapped at 80 columns.
+func renderArticleText(article model.Article) (string, error) {
+	content, err := htmlconv.ToText(article.ContentHTML, htmlconv.DefaultWidth)
+	if err != nil {
+		return "", err
+	}
+
+	var paragraphs []string
+	for _, p := range []string{article.Title, articleByline(article), article.Description, content} {
+		if p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n") + "\n", nil
+}
+
+func articleByline(article model.Article) string {
+	var parts []string
+	if article.Author != "" {
+		parts = append(parts, "By "+article.Author)
+	}
+	if !article.PublishedAt.IsZero() {
+		parts = append(parts, article.PublishedAt.Format("2 January 2006 15:04"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// autolinkEscaper encodes the characters which end a CommonMark autolink.
+var autolinkEscaper = strings.NewReplacer(" ", "%20", "<", "%3C", ">", "%3E")
+
+// collapseSpace keeps text on a single line, a heading or quote ends at the line break.
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/fir1/news/internal/news/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRenderArticleMarkdown(t *testing.T) {
+	markdown, err := renderArticleMarkdown(model.Article{
+		Title:        "# Breaking: *markets*\nrally",
+		Description:  "> 5 things\nto know",
+		ContentHTML:  "<p>Body</p>",
+		Link:         "https://www.bbc.co.uk/news/1",
+		CanonicalURL: "https://www.bbc.co.uk/news/canonical 1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "# \\# Breaking: \\*markets\\* rally\n\n"+
+		"> \\> 5 things to know\n\n"+
+		"Body\n\n"+
+		"---\n\nSource: <https://www.bbc.co.uk/news/canonical%201>\n", markdown)
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+	"time"
+)
+
+const (
+	cacheBackendMemory = "memory"
+	cacheBackendRedis  = "redis"
+	cacheBackendDisk   = "disk"
+)
+
+const (
+	// redisPingTimeout bounds the connection check at start up.
+	redisPingTimeout = 5 * time.Second
+	// redisInvalidationChannel is the pub/sub channel, below the key prefix, the
+	// replicas fan out their L1 invalidations on
+	redisInvalidationChannel = "invalidations"
+)
+
+// NewCacheClient returns the cache selected by CACHE_BACKEND, an unreachable redis
+// server fails the start instead of every request. With CACHE_L1_ENABLED a shared
+// backend gets an in memory cache in front of it, with CACHE_COMPRESSION_ENABLED the
+// entries of both tiers are compressed. The redis client, the invalidation subscription
+// and the cache file are closed when the application stops.
+func NewCacheClient(lc fx.Lifecycle, cnf config.Config) (cache.CacheClientInterface, error) {
+	backend := &cacheBackend{}
+	client, err := backend.open(cnf)
+	if err != nil {
+		return nil, errors.Join(err, backend.close())
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return backend.close()
+		},
+	})
+
+	if !cnf.CacheCompressionEnabled {
+		return client, nil
+	}
+	return cache.NewCompressed(client, cnf.CacheCompressionThreshold), nil
+}
+
+// cacheBackend keeps what has to be closed once the cache is not used anymore.
+type cacheBackend struct {
+	// closers are called in reverse order, the last opened is closed first
+	closers []func() error
+}
+
+func (b *cacheBackend) onClose(closer func() error) {
+	b.closers = append(b.closers, closer)
+}
+
+func (b *cacheBackend) close() error {
+	var errs []error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		errs = append(errs, b.closers[i]())
+	}
+	b.closers = nil
+	return errors.Join(errs...)
+}
+
+func (b *cacheBackend) open(cnf config.Config) (cache.CacheClientInterface, error) {
+	switch cnf.CacheBackend {
+	case cacheBackendMemory:
+		// bigcache evicts every entry after its life window, it has to fit the longest TTL
+		lifeWindow := cnf.CacheTTL
+		for _, ttl := range []time.Duration{cnf.CacheNewsTTL, cnf.CacheArticleTTL} {
+			if ttl > lifeWindow {
+				lifeWindow = ttl
+			}
+		}
+		// stale entries are kept on top of their TTL
+		return cache.NewBigcache(lifeWindow + cnf.CacheMaxStale)
+	case cacheBackendRedis:
+		client := redis.NewClient(&redis.Options{
+			Addr:     cnf.RedisAddr,
+			Password: cnf.RedisPassword,
+			DB:       cnf.RedisDB,
+		})
+		b.onClose(client.Close)
+
+		ctx, cancel := context.WithTimeout(context.Background(), redisPingTimeout)
+		defer cancel()
+		err := client.Ping(ctx).Err()
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to redis at %s: %w", cnf.RedisAddr, err)
+		}
+
+		l2 := cache.NewRedis(client, cnf.RedisKeyPrefix, cnf.CacheTTL)
+		if !cnf.CacheL1Enabled {
+			return l2, nil
+		}
+
+		bus, err := cache.NewRedisBus(client, cnf.RedisKeyPrefix+redisInvalidationChannel)
+		if err != nil {
+			return nil, err
+		}
+		return b.openTwoTier(cnf, l2, bus)
+	case cacheBackendDisk:
+		l2, err := cache.NewDisk(cnf.CacheDiskPath, cnf.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("could not open the cache file %s: %w", cnf.CacheDiskPath, err)
+		}
+		b.onClose(l2.Close)
+		if !cnf.CacheL1Enabled {
+			return l2, nil
+		}
+		// the file is not shared with other replicas, there is nobody to tell about writes
+		return b.openTwoTier(cnf, l2, nil)
+	}
+	return nil, fmt.Errorf("cache backend %q is invalid must be `%s`, `%s`, `%s`", cnf.CacheBackend, cacheBackendMemory, cacheBackendRedis, cacheBackendDisk)
+}
+
+func (b *cacheBackend) openTwoTier(cnf config.Config, l2 cache.CacheClientInterface, bus cache.InvalidationBus) (cache.CacheClientInterface, error) {
+	l1, err := cache.NewBigcache(cnf.CacheL1TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// the invalidations are received until the cache is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	b.onClose(func() error {
+		cancel()
+		return nil
+	})
+	return cache.NewTwoTier(ctx, l1, l2, cnf.CacheL1TTL, bus)
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	newsModel "github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/fir1/news/pkg/urlnorm"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// normalizeListNewsRequest sorts and de-duplicates the providers and categories and
+// applies the defaults of the news service, so equivalent requests look the same.
+func normalizeListNewsRequest(request listNewsRequest) listNewsRequest {
+	if request.Categories == nil {
+		request.Categories = &[]string{"general"}
+	}
+	categories := uniqueSorted(*request.Categories)
+	request.Categories = &categories
+
+	if request.Providers == nil && request.NewsSourceURL == nil {
+		request.Providers = &[]string{string(newsModel.NewsProviderBBC), string(newsModel.NewsProviderSky)}
+	}
+	if request.Providers != nil {
+		providers := uniqueSorted(*request.Providers)
+		request.Providers = &providers
+	}
+
+	if request.SortByPublishDate == "" {
+		request.SortByPublishDate = string(newsSvc.SortDESC)
+	}
+	return request
+}
+
+// newsCacheKey builds the cache key of a request normalized by normalizeListNewsRequest.
+func newsCacheKey(request listNewsRequest) string {
+	values := url.Values{}
+	values.Set("categories", strings.Join(*request.Categories, ","))
+	if request.Providers != nil {
+		values.Set("providers", strings.Join(*request.Providers, ","))
+	}
+	if request.NewsSourceURL != nil {
+		values.Set("news_source_url", urlnorm.Canonical(*request.NewsSourceURL))
+	}
+	values.Set("sort_by_publish_date", request.SortByPublishDate)
+	// Encode sorts the values by key
+	return "news:" + values.Encode()
+}
+
+// articleCacheKey is shared by every format of the article, they are all rendered from
+// the same extracted article.
+func articleCacheKey(articleURL string) string {
+	return "article:" + urlnorm.Canonical(articleURL)
+}
+
+func uniqueSorted(values []string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewsCacheKey(t *testing.T) {
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name   string
+		query  string
+		sameAs string
+	}{
+		{
+			name:   "Defaults",
+			query:  "",
+			sameAs: "providers=sky&providers=bbc&categories=general&sort_by_publish_date=DESC",
+		},
+		{
+			name:   "ReversedOrder",
+			query:  "categories=general&providers=bbc",
+			sameAs: "providers=bbc&categories=general",
+		},
+		{
+			name:   "TrailingAmpersand",
+			query:  "providers=bbc&",
+			sameAs: "providers=bbc",
+		},
+		{
+			name:   "DuplicateValues",
+			query:  "providers=sky&providers=bbc&providers=sky&categories=technology&categories=general",
+			sameAs: "providers=bbc&providers=sky&categories=general&categories=technology",
+		},
+		{
+			name:   "SourceURL",
+			query:  "news_source_url=HTTP://Feeds.Example.com:80/rss.xml%23top",
+			sameAs: "news_source_url=http://feeds.example.com/rss.xml&categories=general",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, newsCacheKeyOf(t, tc.sameAs), newsCacheKeyOf(t, tc.query))
+		})
+	}
+
+	assert.NotEqual(t, newsCacheKeyOf(t, "providers=bbc"), newsCacheKeyOf(t, "providers=sky"))
+	assert.NotEqual(t, newsCacheKeyOf(t, "sort_by_publish_date=ASC"), newsCacheKeyOf(t, "sort_by_publish_date=DESC"))
+}
+
+func newsCacheKeyOf(t *testing.T, query string) string {
+	request := listNewsRequest{}
+	err := parseQueryParamsToStruct(httptest.NewRequest("GET", "/news?"+query, nil), &request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newsCacheKey(normalizeListNewsRequest(request))
+}
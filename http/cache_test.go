@@ -0,0 +1,65 @@
+package http
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewCacheClientClosesBackend(t *testing.T) {
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name    string
+		backend string
+		l1      bool
+	}{
+		{name: "Disk", backend: cacheBackendDisk},
+		{name: "DiskWithL1", backend: cacheBackendDisk, l1: true},
+		{name: "Redis", backend: cacheBackendRedis},
+		{name: "RedisWithL1", backend: cacheBackendRedis, l1: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			cnf := config.Config{
+				CacheBackend:   tc.backend,
+				CacheTTL:       time.Minute,
+				CacheL1Enabled: tc.l1,
+				CacheL1TTL:     time.Minute,
+				CacheDiskPath:  filepath.Join(t.TempDir(), "cache.db"),
+				RedisAddr:      mr.Addr(),
+				RedisKeyPrefix: "news:",
+			}
+
+			lc := fxtest.NewLifecycle(t)
+			client, err := NewCacheClient(lc, cnf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.NoError(t, client.Set("news:categories=general", []byte("news")))
+
+			lc.RequireStart()
+			lc.RequireStop()
+
+			switch tc.backend {
+			case cacheBackendDisk:
+				// the file lock is released, another process could open the cache now
+				disk, err := cache.NewDisk(cnf.CacheDiskPath, cnf.CacheTTL)
+				if assert.NoError(t, err) {
+					assert.NoError(t, disk.Close())
+				}
+			case cacheBackendRedis:
+				// the connections, including the one of the invalidation subscription, are closed
+				assert.Eventually(t, func() bool {
+					return mr.CurrentConnectionCount() == 0
+				}, time.Second, 10*time.Millisecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/fir1/news/internal/news/model"
+	"go.uber.org/fx"
+	"net/url"
+	"time"
+)
+
+// cacheWarmer refreshes the common news lists and the top articles before they turn
+// stale, so users do not wait for the feeds after a deploy or a quiet period.
+type cacheWarmer struct {
+	service *Service
+	// news lists warmed on every run, on top of the most requested ones
+	newsRequests []listNewsRequest
+	interval     time.Duration
+	topNews      int
+	topArticles  int
+}
+
+// RegisterCacheWarmer warms the cache when the application starts, the server starts
+// listening once the first run is done, and then every CACHE_WARM_INTERVAL until it stops.
+func RegisterCacheWarmer(lc fx.Lifecycle, s *Service) error {
+	if !s.config.CacheWarmEnabled {
+		return nil
+	}
+
+	warmer, err := newCacheWarmer(s)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(startCtx context.Context) error {
+			warmCtx, cancelWarm := context.WithTimeout(startCtx, s.config.CacheWarmStartupTimeout)
+			defer cancelWarm()
+			warmer.warm(warmCtx)
+
+			go func() {
+				defer close(done)
+				warmer.warmPeriodically(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+	return nil
+}
+
+func newCacheWarmer(s *Service) (*cacheWarmer, error) {
+	warmer := &cacheWarmer{
+		service:     s,
+		interval:    s.config.CacheWarmInterval,
+		topNews:     s.config.CacheWarmTopNews,
+		topArticles: s.config.CacheWarmTopArticles,
+	}
+
+	for _, query := range s.config.CacheWarmNewsQueries {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, fmt.Errorf("CACHE_WARM_NEWS_QUERIES: %s is invalid: %w", query, err)
+		}
+
+		request := listNewsRequest{}
+		err = decodeQueryValues(values, &request)
+		if err != nil {
+			return nil, fmt.Errorf("CACHE_WARM_NEWS_QUERIES: %s is invalid: %w", query, err)
+		}
+		warmer.newsRequests = append(warmer.newsRequests, normalizeListNewsRequest(request))
+	}
+	return warmer, nil
+}
+
+func (c *cacheWarmer) warmPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.warm(ctx)
+		}
+	}
+}
+
+// warm refreshes the news lists and articles which would turn stale before the next run.
+func (c *cacheWarmer) warm(ctx context.Context) {
+	s := c.service
+	start := time.Now()
+
+	// the most requested articles first, topped up with the latest news of the warmed lists
+	articleURLs := s.popularArticles.top(c.topArticles)
+	var newsLinks []string
+
+	requests := append(append([]listNewsRequest(nil), c.newsRequests...), s.popularNews.top(c.topNews)...)
+	warmed := make(map[string]bool, len(requests))
+	for _, request := range requests {
+		key := newsCacheKey(request)
+		if ctx.Err() != nil {
+			return
+		}
+		if warmed[key] {
+			continue
+		}
+		warmed[key] = true
+
+		response, err := s.newsCache.Warm(ctx, key, c.interval, func(ctx context.Context) (listNewsResponse, error) {
+			return s.loadNews(ctx, request)
+		})
+		if err != nil {
+			s.logger.Warnf("cache warmer: news %s: %v", key, err)
+			continue
+		}
+		for _, news := range response.News {
+			newsLinks = append(newsLinks, news.Link)
+		}
+	}
+
+	for _, link := range newsLinks {
+		if len(articleURLs) >= c.topArticles {
+			break
+		}
+		articleURLs = append(articleURLs, link)
+	}
+
+	for _, articleURL := range articleURLs {
+		key := articleCacheKey(articleURL)
+		if ctx.Err() != nil {
+			return
+		}
+		if warmed[key] {
+			continue
+		}
+		warmed[key] = true
+
+		_, err := s.articleCache.Warm(ctx, key, c.interval, func(ctx context.Context) (model.Article, error) {
+			return s.newsService.GetArticle(ctx, articleURL)
+		})
+		if err != nil {
+			s.logger.Warnf("cache warmer: %s: %v", key, err)
+		}
+	}
+
+	// requests seen before this run count less than the ones of the next interval
+	s.popularNews.decay()
+	s.popularArticles.decay()
+	s.logger.Infof("cache warmer: checked %d entries in %s", len(warmed), time.Since(start))
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"testing"
+	"time"
+)
+
+// MockNewsService is a mock implementation of the news service
+type MockNewsService struct {
+	mock.Mock
+}
+
+func (m *MockNewsService) GetArticle(ctx context.Context, articleURL string) (model.Article, error) {
+	args := m.Called(articleURL)
+	return args.Get(0).(model.Article), args.Error(1)
+}
+
+func (m *MockNewsService) ListNews(ctx context.Context, params newsSvc.ListNewsParams) (newsSvc.ListNewsResponse, error) {
+	args := m.Called(*params.Categories)
+	return args.Get(0).(newsSvc.ListNewsResponse), args.Error(1)
+}
+
+func TestCacheWarmer(t *testing.T) {
+	cc, err := cache.NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	news := new(MockNewsService)
+	news.On("ListNews", []string{"technology"}).Return(newsSvc.ListNewsResponse{
+		NewsFeeds: []model.NewsFeed{
+			{Title: "Latest", Link: "https://www.bbc.co.uk/news/latest"},
+			{Title: "Older", Link: "https://www.bbc.co.uk/news/older"},
+		},
+	}, nil)
+	news.On("GetArticle", "https://www.sky.com/story/popular").Return(model.Article{Title: "Popular"}, nil)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/latest").Return(model.Article{Title: "Latest"}, nil)
+
+	s := NewService(logger, news, config.Config{
+		CacheNewsTTL:         5 * time.Minute,
+		CacheArticleTTL:      time.Hour,
+		CacheWarmInterval:    4 * time.Minute,
+		CacheWarmNewsQueries: []string{"categories=technology"},
+		CacheWarmTopNews:     5,
+		CacheWarmTopArticles: 2,
+	}, cc, nil)
+	// requested often enough to stay popular over both runs
+	for i := 0; i < 2; i++ {
+		s.popularArticles.record(articleCacheKey("https://www.sky.com/story/popular"), "https://www.sky.com/story/popular")
+	}
+
+	warmer, err := newCacheWarmer(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warmer.warm(context.Background())
+
+	response, meta, err := s.newsCache.GetWithMeta(newsCacheKey(warmer.newsRequests[0]))
+	assert.NoError(t, err)
+	assert.Equal(t, cache.StatusHit, meta.Status)
+	assert.Len(t, response.News, 2)
+
+	// the most requested article is topped up with the latest news
+	article, err := s.articleCache.Get(articleCacheKey("https://www.sky.com/story/popular"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Popular", article.Title)
+	article, err = s.articleCache.Get(articleCacheKey("https://www.bbc.co.uk/news/latest"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Latest", article.Title)
+	_, err = s.articleCache.Get(articleCacheKey("https://www.bbc.co.uk/news/older"))
+	assert.ErrorIs(t, err, cache.ErrEntryNotFound)
+
+	// entries staying fresh until the next run are not loaded again
+	warmer.warm(context.Background())
+	news.AssertNumberOfCalls(t, "ListNews", 1)
+	news.AssertNumberOfCalls(t, "GetArticle", 2)
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		s.config.CacheWarmNewsQueries = []string{"categories=%zz"}
+		_, err := newCacheWarmer(s)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingZstd   = "zstd"
+	encodingGzip   = "gzip"
+)
+
+const (
+	// brotliLevel trades a little ratio for the speed dynamic responses need
+	brotliLevel = 5
+	// zstdWindowSize keeps the window far below the 8 MiB browsers decode
+	zstdWindowSize = 1 << 20
+)
+
+// supportedEncodings in the order of preference when the client accepts several equally.
+var supportedEncodings = []string{encodingBrotli, encodingZstd, encodingGzip}
+
+// encoder is implemented by the writers of every supported encoding, they are pooled
+// and reset for every response.
+type encoder interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+var encoders = map[string]*sync.Pool{
+	encodingBrotli: {New: func() any { return brotli.NewWriterLevel(io.Discard, brotliLevel) }},
+	encodingZstd: {New: func() any {
+		// the options are valid, NewWriter does not fail with them
+		writer, _ := zstd.NewWriter(io.Discard,
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithWindowSize(zstdWindowSize),
+		)
+		return writer
+	}},
+	encodingGzip: {New: func() any { return gzip.NewWriter(io.Discard) }},
+}
+
+// compress compresses the responses of at least minSize bytes with the encoding the client
+// prefers. Responses which are already encoded, e.g. gzipped cache entries, or whose
+// content type is compressed itself are sent as they are.
+func compress(minSize int) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addVary(w.Header(), "Accept-Encoding")
+
+			encoding := negotiateEncoding(r)
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the supported encoding with the highest quality in the
+// Accept-Encoding header of the request, or "" when the client accepts none of them.
+func negotiateEncoding(r *http.Request) string {
+	var best string
+	bestQuality := 0.0
+	for _, encoding := range supportedEncodings {
+		quality := encodingQuality(r, encoding)
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// compressResponseWriter buffers the beginning of a response until it is known whether
+// it is worth compressing.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	buffer      bytes.Buffer
+	encoder     encoder
+	wroteHeader bool
+}
+
+func (c *compressResponseWriter) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+}
+
+func (c *compressResponseWriter) Write(data []byte) (int, error) {
+	c.WriteHeader(http.StatusOK)
+	if c.encoder != nil {
+		return c.encoder.Write(data)
+	}
+	if c.wroteHeader {
+		return c.ResponseWriter.Write(data)
+	}
+
+	c.buffer.Write(data)
+	if c.buffer.Len() < c.minSize {
+		return len(data), nil
+	}
+
+	err := c.start(c.compressible())
+	return len(data), err
+}
+
+// Close writes the buffered response and finishes the compressed stream.
+func (c *compressResponseWriter) Close() error {
+	if !c.wroteHeader {
+		if c.status == 0 {
+			c.status = http.StatusOK
+		}
+		// the response ended below minSize
+		err := c.start(false)
+		if err != nil {
+			return err
+		}
+	}
+	if c.encoder == nil {
+		return nil
+	}
+
+	err := c.encoder.Close()
+	c.encoder.Reset(io.Discard)
+	encoders[c.encoding].Put(c.encoder)
+	c.encoder = nil
+	return err
+}
+
+// start writes the headers and the buffered beginning of the response.
+func (c *compressResponseWriter) start(compressed bool) error {
+	c.wroteHeader = true
+	if compressed {
+		header := c.Header()
+		header.Set("Content-Encoding", c.encoding)
+		header.Del("Content-Length")
+		// the compressed body differs from the one the strong ETag was computed from
+		if etag := header.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			header.Set("ETag", "W/"+etag)
+		}
+
+		c.encoder = encoders[c.encoding].Get().(encoder)
+		c.encoder.Reset(c.ResponseWriter)
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+
+	data := c.buffer.Bytes()
+	c.buffer = bytes.Buffer{}
+	if c.encoder != nil {
+		_, err := c.encoder.Write(data)
+		return err
+	}
+	_, err := c.ResponseWriter.Write(data)
+	return err
+}
+
+// compressible reports whether the response may be compressed, the status and headers
+// are final once the handler writes the body.
+func (c *compressResponseWriter) compressible() bool {
+	if c.status < http.StatusOK || c.status == http.StatusNoContent || c.status == http.StatusNotModified {
+		return false
+	}
+
+	header := c.Header()
+	if header.Get("Content-Encoding") != "" || strings.Contains(header.Get("Cache-Control"), "no-transform") {
+		return false
+	}
+	return !incompressibleContentType(header.Get("Content-Type"))
+}
+
+// incompressibleContentType reports content types which are compressed themselves.
+func incompressibleContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	if strings.HasPrefix(contentType, "image/svg") {
+		return false
+	}
+	for _, prefix := range []string{"image/", "video/", "audio/", "font/woff", "application/zip", "application/gzip", "application/x-gzip", "application/zstd", "application/pdf"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"title":"news"},`, 200)
+
+	testCases := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		encoded        string
+		body           string
+		encoding       string
+	}{
+		{
+			name:           "Brotli",
+			acceptEncoding: "gzip, deflate, br",
+			body:           large,
+			encoding:       "br",
+		},
+		{
+			name:           "Zstd",
+			acceptEncoding: "gzip;q=0.5, zstd",
+			body:           large,
+			encoding:       "zstd",
+		},
+		{
+			name:           "Gzip",
+			acceptEncoding: "gzip",
+			body:           large,
+			encoding:       "gzip",
+		},
+		{
+			name:           "Wildcard",
+			acceptEncoding: "*, br;q=0",
+			body:           large,
+			encoding:       "zstd",
+		},
+		{
+			name:           "NotAccepted",
+			acceptEncoding: "identity",
+			body:           large,
+		},
+		{
+			name:           "BelowMinSize",
+			acceptEncoding: "gzip",
+			body:           `{"news":[]}`,
+		},
+		{
+			name:           "AlreadyEncoded",
+			acceptEncoding: "br, gzip",
+			encoded:        "gzip",
+			body:           large,
+			encoding:       "gzip",
+		},
+		{
+			name:           "CompressedContentType",
+			acceptEncoding: "gzip",
+			contentType:    "image/png",
+			body:           large,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType := tc.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				w.Header().Set("Content-Type", contentType)
+				w.Header().Set("ETag", `"tag"`)
+				if tc.encoded != "" {
+					w.Header().Set("Content-Encoding", tc.encoded)
+				}
+				w.WriteHeader(http.StatusOK)
+				// written in pieces like a JSON encoder does
+				for i := 0; i < len(tc.body); i += 100 {
+					_, _ = w.Write([]byte(tc.body[i:min(i+100, len(tc.body))]))
+				}
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/news", nil)
+			r.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.encoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, []string{"Accept-Encoding"}, w.Header().Values("Vary"))
+			if tc.encoded != "" || tc.encoding == "" {
+				assert.Equal(t, tc.body, w.Body.String())
+				assert.Equal(t, `"tag"`, w.Header().Get("ETag"))
+				return
+			}
+
+			assert.Less(t, w.Body.Len(), len(tc.body))
+			assert.Equal(t, `W/"tag"`, w.Header().Get("ETag"))
+			assert.Equal(t, tc.body, decompress(t, tc.encoding, w.Body.Bytes()))
+		})
+	}
+}
+
+func decompress(t *testing.T, encoding string, data []byte) string {
+	var reader io.Reader
+	switch encoding {
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(data))
+	case "zstd":
+		decoder, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer decoder.Close()
+		reader = decoder
+	case "gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = gzipReader
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// conditionalGET buffers successful responses to tag them with a strong ETag computed from
+// the body, and answers 304 Not Modified when the client already has the same response,
+// by its ETag or else by its Last-Modified time.
+func conditionalGET(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferedResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(buffered, r)
+		if buffered.status != http.StatusOK {
+			buffered.flush()
+			return
+		}
+
+		etag := strongETag(buffered.body.Bytes())
+		w.Header().Set("ETag", etag)
+		if notModified(r, w.Header(), etag) {
+			for _, header := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+				w.Header().Del(header)
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		buffered.flush()
+	})
+}
+
+// strongETag identifies the exact bytes of a body, the gzipped and the plain body of the
+// same value get different tags.
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified evaluates If-None-Match, and only without it If-Modified-Since, as RFC 9110 asks.
+func notModified(r *http.Request, header http.Header, etag string) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			// If-None-Match uses the weak comparison
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
+// bufferedResponseWriter keeps the status and the body until the ETag is known, headers
+// are written to the underlying ResponseWriter right away.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	// like net/http the first status wins
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponseWriter) Write(data []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(data)
+}
+
+func (b *bufferedResponseWriter) flush() {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	b.ResponseWriter.WriteHeader(b.status)
+	_, _ = b.ResponseWriter.Write(b.body.Bytes())
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConditionalGET(t *testing.T) {
+	lastModified := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	handler := conditionalGET(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			http.Error(w, "upstream failed", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"news":[]}`))
+	}))
+	etag := strongETag([]byte(`{"news":[]}`))
+
+	testCases := []struct {
+		name   string
+		target string
+		header map[string]string
+		status int
+		etag   string
+	}{
+		{
+			name:   "WithoutValidators",
+			target: "/news",
+			status: http.StatusOK,
+			etag:   etag,
+		},
+		{
+			name:   "MatchingETag",
+			target: "/news",
+			header: map[string]string{"If-None-Match": `"other", ` + etag},
+			status: http.StatusNotModified,
+			etag:   etag,
+		},
+		{
+			name:   "WeakMatchingETag",
+			target: "/news",
+			header: map[string]string{"If-None-Match": "W/" + etag},
+			status: http.StatusNotModified,
+			etag:   etag,
+		},
+		{
+			name:   "OutdatedETag",
+			target: "/news",
+			header: map[string]string{"If-None-Match": `"other"`},
+			status: http.StatusOK,
+			etag:   etag,
+		},
+		{
+			name:   "NotModifiedSince",
+			target: "/news",
+			header: map[string]string{"If-Modified-Since": lastModified.Add(time.Minute).Format(http.TimeFormat)},
+			status: http.StatusNotModified,
+			etag:   etag,
+		},
+		{
+			name:   "ModifiedSince",
+			target: "/news",
+			header: map[string]string{"If-Modified-Since": lastModified.Add(-time.Minute).Format(http.TimeFormat)},
+			status: http.StatusOK,
+			etag:   etag,
+		},
+		{
+			name:   "IfNoneMatchTakesPrecedence",
+			target: "/news",
+			header: map[string]string{
+				"If-None-Match":     `"other"`,
+				"If-Modified-Since": lastModified.Format(http.TimeFormat),
+			},
+			status: http.StatusOK,
+			etag:   etag,
+		},
+		{
+			name:   "ErrorsAreNotTagged",
+			target: "/news?fail=1",
+			header: map[string]string{"If-None-Match": "*"},
+			status: http.StatusBadGateway,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			for key, value := range tc.header {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.etag, w.Header().Get("ETag"))
+			switch tc.status {
+			case http.StatusOK:
+				assert.Equal(t, `{"news":[]}`, w.Body.String())
+			case http.StatusNotModified:
+				assert.Empty(t, w.Body.String())
+				assert.Empty(t, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
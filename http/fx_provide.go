@@ -1,7 +1,6 @@
 package http
 
 import (
-	"github.com/fir1/news/pkg/cache"
 	"github.com/sirupsen/logrus"
 	"go.uber.org/fx"
 	"os"
@@ -10,7 +9,7 @@ import (
 var FxProvide = fx.Provide(
 	NewTextLogger,
 	NewService,
-	cache.NewBigcache,
+	NewCacheClient,
 )
 
 func NewTextLogger() *logrus.Logger {
@@ -0,0 +1,331 @@
+package http
+
+import (
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/graph-gophers/graphql-go"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+//go:embed schema.graphql
+var graphqlSchema string
+
+const (
+	// graphqlMaxDepth allows news { nodes { article { keywords } } } and a little more
+	graphqlMaxDepth = 10
+	// graphqlMaxParallelism bounds the articles extracted at once for a single query
+	graphqlMaxParallelism = 10
+	// graphqlMaxArticles bounds the articles resolved by a single query, aliases would
+	// let a query of a few lines fetch hundreds of articles otherwise
+	graphqlMaxArticles = 20
+)
+
+const newsCursorPrefix = "news:"
+
+func newGraphQLSchema(s *Service) *graphql.Schema {
+	return graphql.MustParseSchema(graphqlSchema, &graphqlResolver{s: s},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxParallelism(graphqlMaxParallelism),
+	)
+}
+
+// graphqlError carries the code of an error of the news service in the extensions of the
+// GraphQL error, like the type of a problem does for the REST API.
+type graphqlError struct {
+	err error
+}
+
+func (e graphqlError) Error() string {
+	return e.err.Error()
+}
+
+func (e graphqlError) Unwrap() error {
+	return e.err
+}
+
+func (e graphqlError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": newsSvc.Code(e.err)}
+}
+
+type graphqlResolver struct {
+	s *Service
+}
+
+type newsArgs struct {
+	Providers       *[]string
+	Categories      *[]string
+	NewsSourceURL   *string
+	PublishedAfter  *graphql.Time
+	PublishedBefore *graphql.Time
+	Q               *string
+	Sort            string
+	First           int32
+	After           *string
+}
+
+// News collects the news through the same cache as GET /news, the filters and the
+// pagination are applied to the cached list.
+func (g *graphqlResolver) News(ctx context.Context, args newsArgs) (*newsConnectionResolver, error) {
+	if args.First < 1 || args.First > maxPerPage {
+		return nil, graphqlError{newsSvc.ErrArgument{Err: fmt.Errorf("first: %d is invalid must be between 1 and %d", args.First, maxPerPage)}}
+	}
+	start := 0
+	if args.After != nil {
+		offset, err := decodeNewsCursor(*args.After)
+		if err != nil {
+			return nil, graphqlError{newsSvc.ErrArgument{Err: err}}
+		}
+		start = offset + 1
+	}
+
+	request := listNewsRequest{
+		Providers:         lowerAll(args.Providers),
+		Categories:        lowerAll(args.Categories),
+		NewsSourceURL:     args.NewsSourceURL,
+		SortByPublishDate: args.Sort,
+	}
+	response, _, err := g.s.getOrLoadNews(ctx, normalizeListNewsRequest(request))
+	if err != nil {
+		return nil, graphqlError{err}
+	}
+
+	news := filterNews(response.News, args)
+	start = min(start, len(news))
+	return &newsConnectionResolver{
+		s:       g.s,
+		news:    news,
+		start:   start,
+		end:     min(start+int(args.First), len(news)),
+		sources: response.Sources,
+	}, nil
+}
+
+func (g *graphqlResolver) Article(ctx context.Context, args struct{ URL string }) (*articleResolver, error) {
+	return resolveArticle(ctx, g.s, args.URL)
+}
+
+// filterNews keeps the news matching the publish date range and the text of the query.
+func filterNews(news []News, args newsArgs) []News {
+	q := ""
+	if args.Q != nil {
+		q = strings.ToLower(*args.Q)
+	}
+
+	result := make([]News, 0, len(news))
+	for _, n := range news {
+		if args.PublishedAfter != nil && !n.PublishDate.After(args.PublishedAfter.Time) {
+			continue
+		}
+		if args.PublishedBefore != nil && !n.PublishDate.Before(args.PublishedBefore.Time) {
+			continue
+		}
+		if q != "" && !strings.Contains(strings.ToLower(n.Title), q) && !strings.Contains(strings.ToLower(n.Description), q) {
+			continue
+		}
+		result = append(result, n)
+	}
+	return result
+}
+
+// lowerAll maps the GraphQL enum values to the values of the REST API.
+func lowerAll(values *[]string) *[]string {
+	if values == nil {
+		return nil
+	}
+	result := make([]string, len(*values))
+	for i, value := range *values {
+		result[i] = strings.ToLower(value)
+	}
+	return &result
+}
+
+// encodeNewsCursor returns the opaque cursor of the news at offset of the filtered list.
+func encodeNewsCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(newsCursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeNewsCursor(cursor string) (int, error) {
+	data, err := base64.StdEncoding.DecodeString(cursor)
+	if err == nil {
+		offset, found := strings.CutPrefix(string(data), newsCursorPrefix)
+		if n, err := strconv.Atoi(offset); found && err == nil && n >= 0 {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("after: %s is not a valid cursor", cursor)
+}
+
+type newsConnectionResolver struct {
+	s          *Service
+	news       []News
+	start, end int
+	sources    []NewsSource
+}
+
+func (c *newsConnectionResolver) Edges() []*newsEdgeResolver {
+	edges := make([]*newsEdgeResolver, 0, c.end-c.start)
+	for i := c.start; i < c.end; i++ {
+		edges = append(edges, &newsEdgeResolver{cursor: encodeNewsCursor(i), node: &newsResolver{s: c.s, news: c.news[i]}})
+	}
+	return edges
+}
+
+func (c *newsConnectionResolver) Nodes() []*newsResolver {
+	nodes := make([]*newsResolver, 0, c.end-c.start)
+	for _, news := range c.news[c.start:c.end] {
+		nodes = append(nodes, &newsResolver{s: c.s, news: news})
+	}
+	return nodes
+}
+
+func (c *newsConnectionResolver) PageInfo() *pageInfoResolver {
+	info := &pageInfoResolver{hasNextPage: c.end < len(c.news)}
+	if c.end > c.start {
+		endCursor := encodeNewsCursor(c.end - 1)
+		info.endCursor = &endCursor
+	}
+	return info
+}
+
+func (c *newsConnectionResolver) TotalCount() int32 {
+	return int32(len(c.news))
+}
+
+func (c *newsConnectionResolver) Sources() []*newsSourceResolver {
+	sources := make([]*newsSourceResolver, len(c.sources))
+	for i, source := range c.sources {
+		sources[i] = &newsSourceResolver{source: source}
+	}
+	return sources
+}
+
+type newsEdgeResolver struct {
+	cursor string
+	node   *newsResolver
+}
+
+func (e *newsEdgeResolver) Cursor() string {
+	return e.cursor
+}
+
+func (e *newsEdgeResolver) Node() *newsResolver {
+	return e.node
+}
+
+type pageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *pageInfoResolver) EndCursor() *string {
+	return p.endCursor
+}
+
+type newsResolver struct {
+	s    *Service
+	news News
+}
+
+func (n *newsResolver) Title() string           { return n.news.Title }
+func (n *newsResolver) Description() string     { return n.news.Description }
+func (n *newsResolver) Link() string            { return n.news.Link }
+func (n *newsResolver) Provider() string        { return n.news.Provider }
+func (n *newsResolver) ProviderLogoURL() string { return n.news.ProviderLogoURL }
+
+func (n *newsResolver) PublishDate() graphql.Time {
+	return graphql.Time{Time: n.news.PublishDate}
+}
+
+// Article is only resolved when the query selects it, the article is shared with
+// GET /articles through the cache.
+func (n *newsResolver) Article(ctx context.Context) (*articleResolver, error) {
+	return resolveArticle(ctx, n.s, n.news.Link)
+}
+
+type articleBudgetKey struct{}
+
+// withArticleBudget lets the query executed with ctx resolve at most articles.
+func withArticleBudget(ctx context.Context, articles int32) context.Context {
+	remaining := articles
+	return context.WithValue(ctx, articleBudgetKey{}, &remaining)
+}
+
+// resolveArticle loads an article once the query has budget left for it, cached articles
+// count as well as the query can not know which ones are.
+func resolveArticle(ctx context.Context, s *Service, articleURL string) (*articleResolver, error) {
+	remaining, ok := ctx.Value(articleBudgetKey{}).(*int32)
+	if ok && atomic.AddInt32(remaining, -1) < 0 {
+		return nil, graphqlError{newsSvc.ErrArgument{
+			Err: fmt.Errorf("article: a query resolves at most %d articles", graphqlMaxArticles),
+		}}
+	}
+
+	article, _, err := s.getOrLoadArticle(ctx, articleURL)
+	if err != nil {
+		return nil, graphqlError{err}
+	}
+	return &articleResolver{article: article}, nil
+}
+
+type newsSourceResolver struct {
+	source NewsSource
+}
+
+func (n *newsSourceResolver) URL() string        { return n.source.URL }
+func (n *newsSourceResolver) Provider() string   { return n.source.Provider }
+func (n *newsSourceResolver) Category() *string  { return optionalString(n.source.Category) }
+func (n *newsSourceResolver) Status() string     { return n.source.Status }
+func (n *newsSourceResolver) Error() *string     { return optionalString(n.source.Error) }
+func (n *newsSourceResolver) ErrorKind() *string { return optionalString(n.source.ErrorKind) }
+func (n *newsSourceResolver) UpstreamStatus() *int32 {
+	if n.source.UpstreamStatus == 0 {
+		return nil
+	}
+	status := int32(n.source.UpstreamStatus)
+	return &status
+}
+
+type articleResolver struct {
+	article model.Article
+}
+
+func (a *articleResolver) Title() string         { return a.article.Title }
+func (a *articleResolver) Description() string   { return a.article.Description }
+func (a *articleResolver) Content() string       { return a.article.Content }
+func (a *articleResolver) ContentHTML() string   { return a.article.ContentHTML }
+func (a *articleResolver) Link() string          { return a.article.Link }
+func (a *articleResolver) CanonicalURL() *string { return optionalString(a.article.CanonicalURL) }
+func (a *articleResolver) ImageURL() *string     { return optionalString(a.article.ImageURL) }
+func (a *articleResolver) SiteName() *string     { return optionalString(a.article.SiteName) }
+func (a *articleResolver) Author() *string       { return optionalString(a.article.Author) }
+func (a *articleResolver) Section() *string      { return optionalString(a.article.Section) }
+func (a *articleResolver) Keywords() []string    { return a.article.Keywords }
+func (a *articleResolver) WordCount() int32      { return int32(a.article.WordCount) }
+func (a *articleResolver) ReadingTimeMinutes() int32 {
+	return int32(a.article.ReadingTimeMinutes)
+}
+
+func (a *articleResolver) PublishedAt() *graphql.Time {
+	if a.article.PublishedAt.IsZero() {
+		return nil
+	}
+	return &graphql.Time{Time: a.article.PublishedAt}
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGraphQL(t *testing.T) {
+	cc, err := cache.NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	published := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+	feeds := make([]model.NewsFeed, 5)
+	for i := range feeds {
+		feeds[i] = model.NewsFeed{
+			Title:       fmt.Sprintf("News %d", i),
+			Link:        fmt.Sprintf("https://www.bbc.co.uk/news/%d", i),
+			PublishDate: published.Add(-time.Duration(i) * time.Hour),
+			Provider:    model.NewsProviderBBC,
+		}
+	}
+	feeds[3].Description = "Markets rally"
+
+	news := new(MockNewsService)
+	news.On("ListNews", []string{"general"}).Return(newsSvc.ListNewsResponse{
+		NewsFeeds: feeds,
+		Sources:   []newsSvc.SourceStatus{{URL: "http://feeds.bbci.co.uk/news/rss.xml", Provider: model.NewsProviderBBC, State: newsSvc.SourceStateOK}},
+	}, nil)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/0").Return(model.Article{
+		Title:   "News 0",
+		Content: "The extracted content",
+		Link:    "https://www.bbc.co.uk/news/0",
+	}, nil)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/missing").Return(model.Article{}, &newsSvc.UpstreamError{
+		Kind:       newsSvc.UpstreamNotFound,
+		URL:        "https://www.bbc.co.uk/news/missing",
+		StatusCode: http.StatusNotFound,
+	})
+
+	s := NewService(logrus.New(), news, config.Config{CacheNewsTTL: time.Minute, CacheArticleTTL: time.Minute}, cc, nil)
+	s.router = chi.NewRouter()
+	s.routes()
+
+	testCases := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		data      string
+		errorCode newsSvc.ErrorCode
+	}{
+		{
+			name:  "NewsWithFirstArticle",
+			query: `{ news(first: 2) { totalCount nodes { title } pageInfo { hasNextPage } } first: news(first: 1) { nodes { article { content } } } }`,
+			data: `{
+				"news": {"totalCount": 5, "nodes": [{"title": "News 0"}, {"title": "News 1"}], "pageInfo": {"hasNextPage": true}},
+				"first": {"nodes": [{"article": {"content": "The extracted content"}}]}
+			}`,
+		},
+		{
+			name:      "NextPage",
+			query:     `query ($after: String) { news(first: 2, after: $after) { edges { node { title } } pageInfo { hasNextPage } } }`,
+			variables: map[string]interface{}{"after": encodeNewsCursor(2)},
+			data:      `{"news": {"edges": [{"node": {"title": "News 3"}}, {"node": {"title": "News 4"}}], "pageInfo": {"hasNextPage": false}}}`,
+		},
+		{
+			name:  "Filters",
+			query: `{ news(providers: [BBC], categories: [GENERAL], publishedBefore: "2023-06-01T11:30:00Z", q: "markets") { totalCount nodes { title publishDate } } }`,
+			data:  `{"news": {"totalCount": 1, "nodes": [{"title": "News 3", "publishDate": "2023-06-01T09:00:00Z"}]}}`,
+		},
+		{
+			name:      "InvalidFirst",
+			query:     `{ news(first: 101) { totalCount } }`,
+			errorCode: newsSvc.CodeInvalidArgument,
+		},
+		{
+			name:      "ArticleNotFound",
+			query:     `{ article(url: "https://www.bbc.co.uk/news/missing") { title } }`,
+			data:      `{"article": null}`,
+			errorCode: newsSvc.CodeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(graphqlRequest{Query: tc.query, Variables: tc.variables})
+			assert.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response struct {
+				Data   json.RawMessage `json:"data"`
+				Errors []struct {
+					Message    string `json:"message"`
+					Extensions struct {
+						Code newsSvc.ErrorCode `json:"code"`
+					} `json:"extensions"`
+				} `json:"errors"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			if tc.data != "" {
+				assert.JSONEq(t, tc.data, string(response.Data))
+			}
+			if tc.errorCode == "" {
+				assert.Empty(t, response.Errors)
+				return
+			}
+			assert.Len(t, response.Errors, 1)
+			assert.Equal(t, tc.errorCode, response.Errors[0].Extensions.Code)
+		})
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(`{ news(first: 1) { nodes { title } } }`), nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data": {"news": {"nodes": [{"title": "News 0"}]}}}`, w.Body.String())
+	})
+
+	t.Run("ArticleBudget", func(t *testing.T) {
+		// aliases resolve the same article again and again, each one counts
+		var query strings.Builder
+		query.WriteString("{")
+		for i := 0; i <= graphqlMaxArticles; i++ {
+			fmt.Fprintf(&query, ` a%d: article(url: "https://www.bbc.co.uk/news/0") { title }`, i)
+		}
+		query.WriteString(" }")
+		body, err := json.Marshal(graphqlRequest{Query: query.String()})
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data   map[string]*struct{ Title string } `json:"data"`
+			Errors []struct {
+				Extensions struct {
+					Code newsSvc.ErrorCode `json:"code"`
+				} `json:"extensions"`
+			} `json:"errors"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		resolved := 0
+		for _, article := range response.Data {
+			if article != nil {
+				resolved++
+			}
+		}
+		assert.Equal(t, graphqlMaxArticles, resolved)
+		if assert.Len(t, response.Errors, 1) {
+			assert.Equal(t, newsSvc.CodeInvalidArgument, response.Errors[0].Extensions.Code)
+		}
+	})
+
+	t.Run("BodyTooLarge", func(t *testing.T) {
+		body, err := json.Marshal(graphqlRequest{Query: "{ news { totalCount } }" + strings.Repeat(" ", graphqlMaxBodyBytes)})
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	})
+
+	t.Run("EmptyQuery", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader([]byte(`{}`))))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	})
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/fir1/news/pkg/cache"
+	"net/http"
+	"strings"
+)
+
+type CacheStats struct {
+	// one-of: memory, redis, disk, two-tier
+	Backend   string `json:"backend"`
+	Hits      int64  `json:"hits"`
+	Misses    int64  `json:"misses"`
+	Evictions int64  `json:"evictions"`
+	Entries   int64  `json:"entries"`
+	// bytes of the keys and values of the memory backend, size of the file of the disk backend
+	Bytes int64 `json:"bytes"`
+	// stats of the in memory L1 and the shared L2 of a two-tier cache
+	Tiers []CacheStats `json:"tiers,omitempty"`
+} // @name CacheStats
+
+type deleteCacheEntriesResponse struct {
+	Deleted int `json:"deleted"`
+} // @name DeleteCacheEntriesResponse
+
+// requireAdmin lets only requests carrying the ADMIN_TOKEN as bearer token through.
+func (s *Service) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.AdminToken == "" {
+			s.respondProblem(w, r, http.StatusForbidden, "admin endpoints are disabled, set ADMIN_TOKEN to enable them")
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AdminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			s.respondProblem(w, r, http.StatusUnauthorized, "invalid or missing admin token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getCacheStats example
+//
+//	@Summary		Get the cache statistics
+//	@Description	 	Get the hits, misses and evictions since the server started together with the number of entries and their size
+//	@Tags Admin
+//	@ID				admin-cache-stats
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//
+// @Success      200 {object}   CacheStats
+// @Failure      401 {object}   Problem
+// @Failure      403 {object}   Problem
+// @Failure      500 {object}   Problem
+// @Failure      501 {object}   Problem
+// @Router			/admin/cache/stats [get].
+func (s *Service) getCacheStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := cache.StatsOf(s.cacheClient)
+	if err != nil {
+		s.respondCacheError(w, r, err)
+		return
+	}
+	s.respond(w, serializeCacheStatsToRestModel(stats), http.StatusOK)
+}
+
+// deleteCacheEntries example
+//
+//	@Summary		Delete cache entries
+//	@Description	 	Delete a single entry by its key, e.g. `article:https://www.bbc.co.uk/news/uk-1`, or every entry whose key starts with the prefix, e.g. `article:https://www.bbc.co.uk/` for all articles of a provider or `news:` for all news lists
+//	@Tags Admin
+//	@ID				admin-cache-entries-delete
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			key		query	string	false	"key of the entry to delete"
+//	@Param			prefix	query	string	false	"prefix of the keys to delete"
+//
+// @Success      200 {object}   DeleteCacheEntriesResponse
+// @Success      204
+// @Failure      400 {object}   Problem
+// @Failure      401 {object}   Problem
+// @Failure      403 {object}   Problem
+// @Failure      500 {object}   Problem
+// @Failure      501 {object}   Problem
+// @Router			/admin/cache/entries [delete].
+func (s *Service) deleteCacheEntries(w http.ResponseWriter, r *http.Request) {
+	key, prefix := r.URL.Query().Get("key"), r.URL.Query().Get("prefix")
+	if (key == "") == (prefix == "") {
+		s.respondProblem(w, r, http.StatusBadRequest, "exactly one of key or prefix must be given")
+		return
+	}
+
+	if key != "" {
+		err := s.cacheClient.Delete(key)
+		if err != nil {
+			s.respondCacheError(w, r, err)
+			return
+		}
+		s.respond(w, nil, http.StatusNoContent)
+		return
+	}
+
+	deleted, err := cache.DeletePrefix(s.cacheClient, prefix)
+	if err != nil {
+		s.respondCacheError(w, r, err)
+		return
+	}
+	s.respond(w, deleteCacheEntriesResponse{Deleted: deleted}, http.StatusOK)
+}
+
+// resetCache example
+//
+//	@Summary		Reset the cache
+//	@Description	 	Delete every entry of the cache, the next requests fetch the news and articles from their sources again
+//	@Tags Admin
+//	@ID				admin-cache-reset
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//
+// @Success      204
+// @Failure      401 {object}   Problem
+// @Failure      403 {object}   Problem
+// @Failure      500 {object}   Problem
+// @Router			/admin/cache/reset [post].
+func (s *Service) resetCache(w http.ResponseWriter, r *http.Request) {
+	err := s.cacheClient.Reset()
+	if err != nil {
+		s.respondCacheError(w, r, err)
+		return
+	}
+	s.respond(w, nil, http.StatusNoContent)
+}
+
+func (s *Service) respondCacheError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, cache.ErrNotSupported) {
+		s.respondProblem(w, r, http.StatusNotImplemented, err.Error())
+		return
+	}
+	s.respondProblem(w, r, http.StatusInternalServerError, err.Error())
+}
+
+func serializeCacheStatsToRestModel(stats cache.Stats) CacheStats {
+	result := CacheStats{
+		Backend:   stats.Backend,
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		Entries:   stats.Entries,
+		Bytes:     stats.Bytes,
+	}
+	for _, tier := range stats.Tiers {
+		result.Tiers = append(result.Tiers, serializeCacheStatsToRestModel(tier))
+	}
+	return result
+}
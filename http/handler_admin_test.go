@@ -0,0 +1,165 @@
+package http
+
+import (
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/fir1/news/pkg/circuitbreaker"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdminCache(t *testing.T) {
+	newService := func(t *testing.T, adminToken string) *Service {
+		cc, err := cache.NewBigcache(time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, cc.Set("news:categories=general", []byte("news")))
+		assert.NoError(t, cc.Set("article:https://www.bbc.co.uk/news/1", []byte("bbc 1")))
+		assert.NoError(t, cc.Set("article:https://www.bbc.co.uk/news/2", []byte("bbc 2")))
+
+		s := &Service{
+			router:      chi.NewRouter(),
+			config:      config.Config{AdminToken: adminToken},
+			cacheClient: cc,
+			breakers:    circuitbreaker.NewRegistry(circuitbreaker.Config{}),
+		}
+		s.routes()
+		return s
+	}
+
+	testCases := []struct {
+		name          string
+		adminToken    string
+		authorization string
+		method        string
+		target        string
+		status        int
+		body          string
+		entries       int64
+	}{
+		{
+			name:          "Disabled",
+			authorization: "Bearer ",
+			method:        http.MethodPost,
+			target:        "/admin/cache/reset",
+			status:        http.StatusForbidden,
+			entries:       3,
+		},
+		{
+			name:       "MissingToken",
+			adminToken: "secret",
+			method:     http.MethodPost,
+			target:     "/admin/cache/reset",
+			status:     http.StatusUnauthorized,
+			entries:    3,
+		},
+		{
+			name:          "WrongToken",
+			adminToken:    "secret",
+			authorization: "Bearer guess",
+			method:        http.MethodPost,
+			target:        "/admin/cache/reset",
+			status:        http.StatusUnauthorized,
+			entries:       3,
+		},
+		{
+			name:          "Stats",
+			adminToken:    "secret",
+			authorization: "Bearer secret",
+			method:        http.MethodGet,
+			target:        "/admin/cache/stats",
+			status:        http.StatusOK,
+			entries:       3,
+		},
+		{
+			name:          "DeleteKey",
+			adminToken:    "secret",
+			authorization: "Bearer secret",
+			method:        http.MethodDelete,
+			target:        "/admin/cache/entries?key=news:categories%3Dgeneral",
+			status:        http.StatusNoContent,
+			entries:       2,
+		},
+		{
+			name:          "DeletePrefix",
+			adminToken:    "secret",
+			authorization: "Bearer secret",
+			method:        http.MethodDelete,
+			target:        "/admin/cache/entries?prefix=article:https://www.bbc.co.uk/",
+			status:        http.StatusOK,
+			body:          `{"deleted":2}`,
+			entries:       1,
+		},
+		{
+			name:          "DeleteWithoutKey",
+			adminToken:    "secret",
+			authorization: "Bearer secret",
+			method:        http.MethodDelete,
+			target:        "/admin/cache/entries",
+			status:        http.StatusBadRequest,
+			entries:       3,
+		},
+		{
+			name:       "CircuitBreakersMissingToken",
+			adminToken: "secret",
+			method:     http.MethodGet,
+			target:     "/diagnostics/circuit-breakers",
+			status:     http.StatusUnauthorized,
+			entries:    3,
+		},
+		{
+			name:          "CircuitBreakers",
+			adminToken:    "secret",
+			authorization: "Bearer secret",
+			method:        http.MethodGet,
+			target:        "/diagnostics/circuit-breakers",
+			status:        http.StatusOK,
+			body:          `{"circuit_breakers":[]}`,
+			entries:       3,
+		},
+		{
+			name:       "CircuitBreakersV1MissingToken",
+			adminToken: "secret",
+			method:     http.MethodGet,
+			target:     "/v1/diagnostics/circuit-breakers",
+			status:     http.StatusUnauthorized,
+			entries:    3,
+		},
+		{
+			name:          "Reset",
+			adminToken:    "secret",
+			authorization: "Bearer secret",
+			method:        http.MethodPost,
+			target:        "/admin/cache/reset",
+			status:        http.StatusNoContent,
+			entries:       0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newService(t, tc.adminToken)
+
+			r := httptest.NewRequest(tc.method, tc.target, nil)
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, w.Body.String())
+			}
+
+			stats, err := cache.StatsOf(s.cacheClient)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.entries, stats.Entries)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"github.com/fir1/news/pkg/circuitbreaker"
+	"net/http"
+	"time"
+)
+
+type listCircuitBreakersResponse struct {
+	CircuitBreakers []CircuitBreaker `json:"circuit_breakers"`
+} // @name ListCircuitBreakersResponse
+
+type CircuitBreaker struct {
+	// canonical URL of the feed guarded by the circuit breaker
+	Source string `json:"source"`
+	// one-of: closed, open, half-open
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+} // @name CircuitBreaker
+
+// listCircuitBreakers example
+//
+//	@Summary		List the circuit breakers of the news feed sources
+//	@Description	 	List the state of the circuit breaker of every news feed source fetched since the server started
+//	@Tags Diagnostics
+//	@ID				diagnostics-circuit-breakers-list
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//
+// @Success      200 {object}   ListCircuitBreakersResponse
+// @Failure      401 {object}   Problem
+// @Failure      403 {object}   Problem
+// @Failure      500
+// @Router			/diagnostics/circuit-breakers [get].
+func (s *Service) listCircuitBreakers(w http.ResponseWriter, r *http.Request) {
+	snapshots := s.breakers.Snapshot()
+
+	response := listCircuitBreakersResponse{
+		CircuitBreakers: make([]CircuitBreaker, len(snapshots)),
+	}
+	for i, snapshot := range snapshots {
+		response.CircuitBreakers[i] = serializeCircuitBreakerToRestModel(snapshot)
+	}
+	s.respond(w, response, http.StatusOK)
+}
+
+func serializeCircuitBreakerToRestModel(snapshot circuitbreaker.Snapshot) CircuitBreaker {
+	result := CircuitBreaker{
+		Source:              snapshot.Name,
+		State:               string(snapshot.State),
+		ConsecutiveFailures: snapshot.ConsecutiveFailures,
+		LastError:           snapshot.LastError,
+	}
+	if !snapshot.OpenedAt.IsZero() {
+		result.OpenedAt = &snapshot.OpenedAt
+	}
+	if !snapshot.LastFailureAt.IsZero() {
+		result.LastFailureAt = &snapshot.LastFailureAt
+	}
+	return result
+}
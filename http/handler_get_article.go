@@ -1,71 +1,85 @@
 package http
 
 import (
-	"encoding/json"
-	"errors"
-	"github.com/allegro/bigcache/v3"
+	"context"
+	"fmt"
 	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/fir1/news/pkg/cache"
 	"html/template"
 	"net/http"
 )
 
 type getArticleRequest struct {
 	URL string `form:"url"`
+	// one-of: html, markdown, text. By default the article is shown as an HTML page,
+	// `markdown` returns CommonMark and `text` returns plain text wrapped at 80 columns.
+	Format string `form:"format"`
 } // @name GetArticleRequest
 
 // getArticle example
 //
 //	@Summary		Get article, it shows a single news article on screen, using an HTML display
-//	@Description	 	Get article, it shows a single news article on screen, using an HTML display
+//	@Description	 	Get article, it shows a single news article on screen, using an HTML display.
+//	@Description	 	Clients sending `Accept: application/json` receive the same payload as `GET /articles`,
+//	@Description	 	the `format` query param returns the article as Markdown or plain text instead.
 //	@Tags News
 //	@ID				article-get
 //	@Accept			json
+//	@Produce		html
 //	@Produce		json
+//	@Produce		plain
+//	@Produce		text/markdown
 //	@Param			query-params query GetArticleRequest false "Get article query params"
 //
 // @Success      200
+// @Header       200 {string}   ETag           "strong validator of the response body"
+// @Header       200 {string}   Cache-Control  "max-age is the time the response stays fresh in the cache"
+// @Success      304 "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
 //
-//	@Failure      400
+//	@Failure      400 {object}   Problem
 //
-// @Failure      500
+// @Failure      404 {object}   Problem
+// @Failure      429 {object}   Problem
+// @Failure      500 {object}   Problem
+// @Failure      502 {object}   Problem
+// @Failure      504 {object}   Problem
 // @Router			/article [get].
 func (s *Service) getArticle(w http.ResponseWriter, r *http.Request) {
+	// the same URL answers with an HTML page or JSON depending on the Accept header
+	addVary(w.Header(), "Accept")
+
 	request := getArticleRequest{}
 	err := parseQueryParamsToStruct(r, &request)
 	if err != nil {
-		s.respond(w, err, 0)
+		s.respondServiceError(w, r, newsSvc.ErrArgument{Err: err})
 		return
 	}
 
-	article := model.Article{}
-	cacheResponse, err := s.cacheClient.Get(r.RequestURI)
-	switch {
-	case err == nil:
-		err = json.Unmarshal(cacheResponse, &article)
-		if err != nil {
-			s.respond(w, err, http.StatusInternalServerError)
-			return
-		}
-	case errors.Is(err, bigcache.ErrEntryNotFound):
-		article, err = s.newsService.GetArticle(r.Context(), request.URL)
-		if err != nil {
-			s.respond(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	switch request.Format {
+	case "", articleFormatHTML, articleFormatMarkdown, articleFormatText:
+	default:
+		s.respondServiceError(w, r, newsSvc.ErrArgument{
+			Err: fmt.Errorf("format: %s is invalid must be `html`, `markdown`, `text`", request.Format),
+		})
+		return
+	}
 
-		responseBytes, err := json.Marshal(&article)
-		if err != nil {
-			s.respond(w, err, http.StatusInternalServerError)
-			return
-		}
+	article, err := s.loadArticle(w, r, request.URL)
+	if err != nil {
+		s.respondServiceError(w, r, err)
+		return
+	}
 
-		err = s.cacheClient.Set(r.RequestURI, responseBytes)
-		if err != nil {
-			s.respond(w, err, http.StatusInternalServerError)
-			return
-		}
-	default:
-		s.respond(w, err, http.StatusInternalServerError)
+	switch {
+	case request.Format == articleFormatMarkdown:
+		s.writeArticle(w, r, article, renderArticleMarkdown, "text/markdown; charset=utf-8")
+		return
+	case request.Format == articleFormatText:
+		s.writeArticle(w, r, article, renderArticleText, "text/plain; charset=utf-8")
+		return
+	case request.Format == "" && prefersJSON(r):
+		s.respond(w, serializeArticleToRestModel(article), http.StatusOK)
 		return
 	}
 
@@ -76,6 +90,9 @@ func (s *Service) getArticle(w http.ResponseWriter, r *http.Request) {
 		</head>
 		<body>
 			<h1>{{ .Title }}</h1>
+			{{ if .Author }}<p>By {{ .Author }}</p>{{ end }}
+			{{ if not .PublishedAt.IsZero }}<p><time datetime="{{ .PublishedAt.Format "2006-01-02T15:04:05Z07:00" }}">{{ .PublishedAt.Format "2 January 2006 15:04" }}</time></p>{{ end }}
+			{{ if .ImageURL }}<img src="{{ .ImageURL }}" alt="{{ .Title }}">{{ end }}
 			<p>{{ .Description }}</p>
 			<hr>
 			{{ .Content | safe }}
@@ -98,3 +115,28 @@ func (s *Service) getArticle(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusOK)
 }
+
+// loadArticle returns the article from the cache or extracts it from the given url,
+// the cache headers of the response are set on success.
+func (s *Service) loadArticle(w http.ResponseWriter, r *http.Request, articleURL string) (model.Article, error) {
+	article, meta, err := s.getOrLoadArticle(r.Context(), articleURL)
+	if err != nil {
+		return model.Article{}, err
+	}
+
+	s.setCacheHeaders(w, meta)
+	return article, nil
+}
+
+// getOrLoadArticle returns the article from the cache or extracts it.
+func (s *Service) getOrLoadArticle(ctx context.Context, articleURL string) (model.Article, cache.Meta, error) {
+	key := articleCacheKey(articleURL)
+	article, meta, err := s.articleCache.GetOrLoad(ctx, key, func(ctx context.Context) (model.Article, error) {
+		return s.newsService.GetArticle(ctx, articleURL)
+	})
+	if err != nil {
+		return model.Article{}, cache.Meta{}, err
+	}
+	s.popularArticles.record(key, articleURL)
+	return article, meta, nil
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"net/http"
+	"time"
+)
+
+type articleResponse struct {
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Content      string     `json:"content"`
+	Link         string     `json:"link"`
+	CanonicalURL string     `json:"canonical_url,omitempty"`
+	ImageURL     string     `json:"image_url,omitempty"`
+	SiteName     string     `json:"site_name,omitempty"`
+	PublishedAt  *time.Time `json:"published_at,omitempty"`
+	Author       string     `json:"author,omitempty"`
+	Section      string     `json:"section,omitempty"`
+	Keywords     []string   `json:"keywords,omitempty"`
+	// number of words in the extracted content
+	WordCount int `json:"word_count"`
+	// estimated reading time of the content in minutes
+	ReadingTimeMinutes int `json:"reading_time_minutes"`
+} // @name ArticleResponse
+
+// getArticleJSON example
+//
+//	@Summary		Get article as JSON
+//	@Description	 	Get article as JSON, it returns the extracted article together with its metadata, word count and reading time
+//	@Tags News
+//	@ID				articles-get
+//	@Accept			json
+//	@Produce		json
+//	@Param			query-params query GetArticleRequest false "Get article query params"
+//
+// @Success      200 {object}   ArticleResponse
+// @Header       200 {string}   ETag           "strong validator of the response body"
+// @Header       200 {string}   Cache-Control  "max-age is the time the response stays fresh in the cache"
+// @Success      304 "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
+//
+//	@Failure      400 {object}   Problem
+//
+// @Failure      404 {object}   Problem
+// @Failure      429 {object}   Problem
+// @Failure      502 {object}   Problem
+// @Failure      504 {object}   Problem
+// @Router			/articles [get].
+func (s *Service) getArticleJSON(w http.ResponseWriter, r *http.Request) {
+	request := getArticleRequest{}
+	err := parseQueryParamsToStruct(r, &request)
+	if err != nil {
+		s.respondServiceError(w, r, newsSvc.ErrArgument{Err: err})
+		return
+	}
+
+	article, err := s.loadArticle(w, r, request.URL)
+	if err != nil {
+		s.respondServiceError(w, r, err)
+		return
+	}
+
+	s.respond(w, serializeArticleToRestModel(article), http.StatusOK)
+}
+
+func serializeArticleToRestModel(article model.Article) articleResponse {
+	response := articleResponse{
+		Title:              article.Title,
+		Description:        article.Description,
+		Content:            article.Content,
+		Link:               article.Link,
+		CanonicalURL:       article.CanonicalURL,
+		ImageURL:           article.ImageURL,
+		SiteName:           article.SiteName,
+		Author:             article.Author,
+		Section:            article.Section,
+		Keywords:           article.Keywords,
+		WordCount:          article.WordCount,
+		ReadingTimeMinutes: article.ReadingTimeMinutes,
+	}
+	if !article.PublishedAt.IsZero() {
+		response.PublishedAt = &article.PublishedAt
+	}
+	return response
+}
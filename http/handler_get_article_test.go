@@ -0,0 +1,83 @@
+package http
+
+import (
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/internal/news/model"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetArticleVariesOnAccept(t *testing.T) {
+	cc, err := cache.NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	news := new(MockNewsService)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/1").Return(model.Article{
+		Title:   "News",
+		Content: "<p>The extracted content</p>",
+		Link:    "https://www.bbc.co.uk/news/1",
+	}, nil)
+
+	s := NewService(logrus.New(), news, config.Config{CacheArticleTTL: time.Minute}, cc, nil)
+	s.router = chi.NewRouter()
+	s.routes()
+
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name        string
+		target      string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{
+			name:        "HTML",
+			target:      "/article?url=https://www.bbc.co.uk/news/1",
+			accept:      "text/html",
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+		},
+		{
+			name:        "JSON",
+			target:      "/article?url=https://www.bbc.co.uk/news/1",
+			accept:      "application/json",
+			status:      http.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			name:        "Markdown",
+			target:      "/article?url=https://www.bbc.co.uk/news/1&format=markdown",
+			accept:      "application/json",
+			status:      http.StatusOK,
+			contentType: "text/markdown; charset=utf-8",
+		},
+		{
+			name:        "InvalidFormat",
+			target:      "/article?url=https://www.bbc.co.uk/news/1&format=pdf",
+			accept:      "application/json",
+			status:      http.StatusBadRequest,
+			contentType: problemContentType,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			r.Header.Set("Accept", tc.accept)
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Header().Values("Vary"), "Accept")
+		})
+	}
+}
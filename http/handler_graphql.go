@@ -0,0 +1,82 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"net/http"
+)
+
+// graphqlMaxBodyBytes bounds the body of a POST, queries are a few lines long.
+const graphqlMaxBodyBytes = 64 << 10
+
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+} // @name GraphQLRequest
+
+type graphqlResponse struct {
+	Data   interface{}   `json:"data,omitempty"`
+	Errors []interface{} `json:"errors,omitempty"`
+} // @name GraphQLResponse
+
+// queryGraphQL example
+//
+//	@Summary		Query news and articles with GraphQL
+//	@Description	 	Run a GraphQL query against the schema in http/schema.graphql, e.g. a page of news together with the extracted article of the first one
+//	@Tags News
+//	@ID				graphql-query
+//	@Accept			json
+//	@Produce		json
+//	@Param			request body GraphQLRequest true "GraphQL request"
+//
+// @Success      200 {object}   GraphQLResponse
+// @Failure      400 {object}   Problem
+// @Failure      413 {object}   Problem
+// @Router			/graphql [post].
+func (s *Service) queryGraphQL(w http.ResponseWriter, r *http.Request) {
+	request, err := decodeGraphQLRequest(w, r)
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		s.respondProblem(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("body must not be larger than %d bytes", maxBytesErr.Limit))
+		return
+	case err != nil:
+		s.respondServiceError(w, r, newsSvc.ErrArgument{Err: err})
+		return
+	}
+
+	ctx := withArticleBudget(r.Context(), graphqlMaxArticles)
+	response := s.graphqlSchema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+	// errors of single fields are reported next to the data of the others
+	s.respond(w, response, http.StatusOK)
+}
+
+// decodeGraphQLRequest reads the request from the JSON body of a POST, or from the
+// query, operationName and variables parameters of a GET.
+func decodeGraphQLRequest(w http.ResponseWriter, r *http.Request) (graphqlRequest, error) {
+	request := graphqlRequest{}
+	if r.Method == http.MethodPost {
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, graphqlMaxBodyBytes)).Decode(&request)
+		if err != nil {
+			return request, fmt.Errorf("body is not a valid GraphQL request: %w", err)
+		}
+	} else {
+		query := r.URL.Query()
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			err := json.Unmarshal([]byte(variables), &request.Variables)
+			if err != nil {
+				return request, fmt.Errorf("variables: %w", err)
+			}
+		}
+	}
+
+	if request.Query == "" {
+		return request, errors.New("query: must not be empty")
+	}
+	return request, nil
+}
@@ -1,11 +1,11 @@
 package http
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"github.com/allegro/bigcache/v3"
 	newsModel "github.com/fir1/news/internal/news/model"
 	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/fir1/news/pkg/cache"
 	"net/http"
 	"time"
 )
@@ -29,8 +29,23 @@ type listNewsRequest struct {
 
 type listNewsResponse struct {
 	News []News `json:"news"`
+	// the outcome of every feed the news were collected from
+	Sources []NewsSource `json:"sources"`
 } // @name ListNewsResponse
 
+type NewsSource struct {
+	URL      string `json:"url"`
+	Provider string `json:"provider"`
+	Category string `json:"category,omitempty"`
+	// one-of: ok, failed, skipped - the circuit breaker of the source is open and it was not fetched
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// one-of: not_found, gone, rate_limited, client_error, server_error, unexpected_status, invalid_feed - set when the feed itself failed
+	ErrorKind string `json:"error_kind,omitempty"`
+	// the HTTP status code the feed responded with when it failed
+	UpstreamStatus int `json:"upstream_status,omitempty"`
+} // @name NewsSource
+
 type News struct {
 	Title           string    `json:"title"`
 	Description     string    `json:"description"`
@@ -51,36 +66,76 @@ type News struct {
 //	@Param			query-params query ListNewsRequest false "List calendar events request"
 //
 // @Success      200 {object}   ListNewsResponse
+// @Header       200 {string}   ETag           "strong validator of the response body"
+// @Header       200 {string}   Cache-Control  "max-age is the time the response stays fresh in the cache"
+// @Success      304 "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
 //
-//	@Failure      400
+//	@Failure      400 {object}   Problem
 //
-// @Failure      500
+// @Failure      404 {object}   Problem
+// @Failure      429 {object}   Problem
+// @Failure      502 {object}   Problem
+// @Failure      504 {object}   Problem
 // @Router			/news [get].
 func (s *Service) listNews(w http.ResponseWriter, r *http.Request) {
 	request := listNewsRequest{}
 	err := parseQueryParamsToStruct(r, &request)
 	if err != nil {
-		s.respond(w, err, 0)
+		s.respondServiceError(w, r, newsSvc.ErrArgument{Err: err})
 		return
 	}
 
-	cacheResponse, err := s.cacheClient.Get(r.RequestURI)
-	switch {
-	case err == nil:
-		response := listNewsResponse{}
-		err = json.Unmarshal(cacheResponse, &response)
-		if err != nil {
-			s.respond(w, err, http.StatusInternalServerError)
+	request = normalizeListNewsRequest(request)
+	key := newsCacheKey(request)
+
+	// a fresh list stored compressed is sent as it is to clients accepting gzip
+	addVary(w.Header(), "Accept-Encoding")
+	if acceptsEncoding(r, cache.EncodingGzip) {
+		encoded, meta, err := s.newsCache.GetEncoded(key)
+		if err == nil && meta.Status == cache.StatusHit && encoded.Encoding == cache.EncodingGzip {
+			s.popularNews.record(key, request)
+			s.setCacheHeaders(w, meta)
+			s.respondEncoded(w, encoded, http.StatusOK)
 			return
 		}
-		s.respond(w, response, http.StatusOK)
-		return
-	case errors.Is(err, bigcache.ErrEntryNotFound):
-	default:
-		s.respond(w, err, http.StatusInternalServerError)
+	}
+
+	response, err := s.cachedNews(w, r, request)
+	if err != nil {
+		s.respondServiceError(w, r, err)
 		return
 	}
+	s.respond(w, response, http.StatusOK)
+}
+
+// cachedNews returns the news of a normalized request from the cache or collects them,
+// the cache headers of the response are set on success.
+func (s *Service) cachedNews(w http.ResponseWriter, r *http.Request, request listNewsRequest) (listNewsResponse, error) {
+	response, meta, err := s.getOrLoadNews(r.Context(), request)
+	if err != nil {
+		return listNewsResponse{}, err
+	}
+
+	s.setCacheHeaders(w, meta)
+	return response, nil
+}
+
+// getOrLoadNews returns the news of a normalized request from the cache or collects them.
+func (s *Service) getOrLoadNews(ctx context.Context, request listNewsRequest) (listNewsResponse, cache.Meta, error) {
+	key := newsCacheKey(request)
+	// concurrent misses of the same list share a single fan out to the feeds
+	response, meta, err := s.newsCache.GetOrLoad(ctx, key, func(ctx context.Context) (listNewsResponse, error) {
+		return s.loadNews(ctx, request)
+	})
+	if err != nil {
+		return listNewsResponse{}, cache.Meta{}, err
+	}
+	s.popularNews.record(key, request)
+	return response, meta, nil
+}
 
+// loadNews collects the news of a normalized request.
+func (s *Service) loadNews(ctx context.Context, request listNewsRequest) (listNewsResponse, error) {
 	var providers *[]newsModel.NewsProvider
 	if request.Providers != nil {
 		prs := make([]newsModel.NewsProvider, len(*request.Providers))
@@ -90,32 +145,53 @@ func (s *Service) listNews(w http.ResponseWriter, r *http.Request) {
 		providers = &prs
 	}
 
-	newsResponse, err := s.newsService.ListNews(r.Context(), newsSvc.ListNewsParams{
+	newsResponse, err := s.newsService.ListNews(ctx, newsSvc.ListNewsParams{
 		Categories:        request.Categories,
 		Providers:         providers,
 		SortByPublishDate: newsSvc.Sort(request.SortByPublishDate),
 		NewsSourceURL:     request.NewsSourceURL,
 	})
 	if err != nil {
-		s.respond(w, err.Error(), http.StatusBadRequest)
-		return
+		return listNewsResponse{}, err
 	}
 
-	response := listNewsResponse{
-		News: serializeNewsToRestModel(newsResponse.NewsFeeds),
-	}
-	responseBytes, err := json.Marshal(&response)
-	if err != nil {
-		s.respond(w, err, http.StatusInternalServerError)
-		return
+	return listNewsResponse{
+		News:    serializeNewsToRestModel(newsResponse.NewsFeeds),
+		Sources: serializeNewsSourcesToRestModel(newsResponse.Sources),
+	}, nil
+}
+
+// allSourcesOK reports whether a list may be cached, a partial list is not cached and
+// the failed sources are tried again with the next request.
+func allSourcesOK(response listNewsResponse) bool {
+	for _, source := range response.Sources {
+		if source.Status != string(newsSvc.SourceStateOK) {
+			return false
+		}
 	}
+	return true
+}
 
-	err = s.cacheClient.Set(r.RequestURI, responseBytes)
-	if err != nil {
-		s.respond(w, err, http.StatusInternalServerError)
-		return
+func serializeNewsSourcesToRestModel(sources []newsSvc.SourceStatus) []NewsSource {
+	result := make([]NewsSource, len(sources))
+	for i, source := range sources {
+		result[i] = NewsSource{
+			URL:      source.URL,
+			Provider: string(source.Provider),
+			Category: source.Category,
+			Status:   string(source.State),
+		}
+		if source.Err != nil {
+			result[i].Error = source.Err.Error()
+		}
+
+		var upstreamErr *newsSvc.UpstreamError
+		if errors.As(source.Err, &upstreamErr) {
+			result[i].ErrorKind = string(upstreamErr.Kind)
+			result[i].UpstreamStatus = upstreamErr.StatusCode
+		}
 	}
-	s.respond(w, response, http.StatusOK)
+	return result
 }
 
 func serializeNewsToRestModel(feeds []newsModel.NewsFeed) []News {
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListNewsServesCompressedEntries(t *testing.T) {
+	backend, err := cache.NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feeds := make([]model.NewsFeed, 50)
+	for i := range feeds {
+		feeds[i] = model.NewsFeed{Title: "Headline", Description: strings.Repeat("news ", 20), Link: "https://www.bbc.co.uk/news/1"}
+	}
+	news := new(MockNewsService)
+	news.On("ListNews", []string{"general"}).Return(newsSvc.ListNewsResponse{NewsFeeds: feeds}, nil)
+
+	s := NewService(logrus.New(), news, config.Config{CacheNewsTTL: time.Minute}, cache.NewCompressed(backend, 1024), nil)
+	s.router = chi.NewRouter()
+	s.routes()
+
+	get := func(acceptEncoding string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/news", nil)
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+		return w
+	}
+
+	miss := get("gzip")
+	assert.Equal(t, http.StatusOK, miss.Code)
+	assert.Equal(t, "MISS", miss.Header().Get("X-Cache"))
+	assert.Equal(t, "public, max-age=60, stale-while-revalidate=0", miss.Header().Get("Cache-Control"))
+	assert.Empty(t, miss.Header().Get("Content-Encoding"))
+
+	hit := get("br, gzip;q=0.8")
+	assert.Equal(t, http.StatusOK, hit.Code)
+	assert.Equal(t, "HIT", hit.Header().Get("X-Cache"))
+	assert.Equal(t, "gzip", hit.Header().Get("Content-Encoding"))
+	assert.Equal(t, "application/json", hit.Header().Get("Content-Type"))
+	assert.Less(t, hit.Body.Len(), miss.Body.Len())
+	// the gzipped body is a different representation of the list
+	assert.NotEqual(t, miss.Header().Get("ETag"), hit.Header().Get("ETag"))
+
+	reader, err := gzip.NewReader(bytes.NewReader(hit.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.JSONEq(t, miss.Body.String(), string(body))
+
+	// clients not accepting gzip get the decompressed list
+	plain := get("gzip;q=0")
+	assert.Equal(t, "HIT", plain.Header().Get("X-Cache"))
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, miss.Body.String(), plain.Body.String())
+
+	news.AssertNumberOfCalls(t, "ListNews", 1)
+}
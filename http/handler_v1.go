@@ -0,0 +1,217 @@
+package http
+
+import (
+	"fmt"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+// legacyRoutesDeprecatedAt is announced in the Deprecation header of the unversioned routes.
+var legacyRoutesDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// apiResponse is the envelope of every /v1 response, successful responses carry data and
+// failed ones errors.
+type apiResponse struct {
+	Data   interface{} `json:"data,omitempty"`
+	Meta   interface{} `json:"meta,omitempty"`
+	Errors []APIError  `json:"errors,omitempty"`
+} // @name APIResponse
+
+type APIError struct {
+	// one-of: invalid_argument, not_found, upstream_unavailable, upstream_rate_limited, timeout,
+	// canceled, internal
+	Code    string `json:"code"`
+	Message string `json:"message"`
+} // @name APIError
+
+type newsMeta struct {
+	Pagination Pagination `json:"pagination"`
+	// the outcome of every feed the news were collected from
+	Sources []NewsSource `json:"sources"`
+} // @name NewsMeta
+
+type Pagination struct {
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	// number of news of all pages
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+} // @name Pagination
+
+type paginationRequest struct {
+	// page of the news starting at 1, 1 by default
+	Page int `form:"page"`
+	// news per page up to 100, 20 by default
+	PerPage int `form:"per_page"`
+} // @name PaginationRequest
+
+// deprecated announces that a route is replaced by successor, clients see it in the
+// Deprecation (RFC 9745) and Link (RFC 8288) headers of every response.
+func deprecated(successor string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", legacyRoutesDeprecatedAt.Unix()))
+			w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// listNewsV1 example
+//
+//	@Summary		List news articles from a public news feed
+//	@Description	 	List a page of the news articles, the meta data holds the pagination and the outcome of every feed
+//	@Tags News
+//	@ID				v1-news-list
+//	@Accept			json
+//	@Produce		json
+//	@Param			query-params query ListNewsRequest false "List news request"
+//	@Param			pagination query PaginationRequest false "Pagination"
+//
+// @Success      200 {object}   APIResponse{data=[]News,meta=NewsMeta}
+// @Header       200 {string}   ETag           "strong validator of the response body"
+// @Header       200 {string}   Cache-Control  "max-age is the time the response stays fresh in the cache"
+// @Success      304 "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
+// @Failure      400 {object}   APIResponse
+// @Failure      404 {object}   APIResponse
+// @Failure      429 {object}   APIResponse
+// @Failure      502 {object}   APIResponse
+// @Failure      504 {object}   APIResponse
+// @Router			/v1/news [get].
+func (s *Service) listNewsV1(w http.ResponseWriter, r *http.Request) {
+	request := listNewsRequest{}
+	err := parseQueryParamsToStruct(r, &request)
+	if err != nil {
+		s.respondV1Error(w, err, http.StatusBadRequest, newsSvc.CodeInvalidArgument)
+		return
+	}
+	page := paginationRequest{}
+	err = parseQueryParamsToStruct(r, &page)
+	if err != nil {
+		s.respondV1Error(w, err, http.StatusBadRequest, newsSvc.CodeInvalidArgument)
+		return
+	}
+	page, err = normalizePaginationRequest(page)
+	if err != nil {
+		s.respondV1Error(w, err, http.StatusBadRequest, newsSvc.CodeInvalidArgument)
+		return
+	}
+
+	response, err := s.cachedNews(w, r, normalizeListNewsRequest(request))
+	if err != nil {
+		s.respondV1ServiceError(w, err)
+		return
+	}
+
+	total := len(response.News)
+	start := min((page.Page-1)*page.PerPage, total)
+	end := min(start+page.PerPage, total)
+	s.respond(w, apiResponse{
+		Data: response.News[start:end],
+		Meta: newsMeta{
+			Pagination: Pagination{
+				Page:       page.Page,
+				PerPage:    page.PerPage,
+				Total:      total,
+				TotalPages: (total + page.PerPage - 1) / page.PerPage,
+			},
+			Sources: response.Sources,
+		},
+	}, http.StatusOK)
+}
+
+// getArticleV1 example
+//
+//	@Summary		Get article as JSON
+//	@Description	 	Get the extracted article together with its metadata, word count and reading time
+//	@Tags News
+//	@ID				v1-articles-get
+//	@Accept			json
+//	@Produce		json
+//	@Param			query-params query GetArticleRequest false "Get article query params"
+//
+// @Success      200 {object}   APIResponse{data=ArticleResponse}
+// @Header       200 {string}   ETag           "strong validator of the response body"
+// @Header       200 {string}   Cache-Control  "max-age is the time the response stays fresh in the cache"
+// @Success      304 "the response did not change since the ETag in If-None-Match or the If-Modified-Since time"
+// @Failure      400 {object}   APIResponse
+// @Failure      404 {object}   APIResponse
+// @Failure      429 {object}   APIResponse
+// @Failure      502 {object}   APIResponse
+// @Failure      504 {object}   APIResponse
+// @Router			/v1/articles [get].
+func (s *Service) getArticleV1(w http.ResponseWriter, r *http.Request) {
+	request := getArticleRequest{}
+	err := parseQueryParamsToStruct(r, &request)
+	if err != nil {
+		s.respondV1Error(w, err, http.StatusBadRequest, newsSvc.CodeInvalidArgument)
+		return
+	}
+
+	article, err := s.loadArticle(w, r, request.URL)
+	if err != nil {
+		s.respondV1ServiceError(w, err)
+		return
+	}
+	s.respond(w, apiResponse{Data: serializeArticleToRestModel(article)}, http.StatusOK)
+}
+
+// listCircuitBreakersV1 example
+//
+//	@Summary		List the circuit breakers of the news feed sources
+//	@Description	 	List the state of the circuit breaker of every news feed source fetched since the server started
+//	@Tags Diagnostics
+//	@ID				v1-diagnostics-circuit-breakers-list
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//
+// @Success      200 {object}   APIResponse{data=[]CircuitBreaker}
+// @Failure      401 {object}   Problem
+// @Failure      403 {object}   Problem
+// @Failure      500
+// @Router			/v1/diagnostics/circuit-breakers [get].
+func (s *Service) listCircuitBreakersV1(w http.ResponseWriter, r *http.Request) {
+	snapshots := s.breakers.Snapshot()
+
+	circuitBreakers := make([]CircuitBreaker, len(snapshots))
+	for i, snapshot := range snapshots {
+		circuitBreakers[i] = serializeCircuitBreakerToRestModel(snapshot)
+	}
+	s.respond(w, apiResponse{Data: circuitBreakers}, http.StatusOK)
+}
+
+func (s *Service) respondV1Error(w http.ResponseWriter, err error, status int, code newsSvc.ErrorCode) {
+	s.respond(w, apiResponse{
+		Errors: []APIError{{Code: string(code), Message: err.Error()}},
+	}, status)
+}
+
+// respondV1ServiceError reports an error of the news service with the status code and
+// the error code respondServiceError reports it with.
+func (s *Service) respondV1ServiceError(w http.ResponseWriter, err error) {
+	s.respondV1Error(w, err, serviceErrorStatus(w, err), newsSvc.Code(err))
+}
+
+func normalizePaginationRequest(request paginationRequest) (paginationRequest, error) {
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = defaultPerPage
+	}
+
+	if request.Page < 1 {
+		return request, fmt.Errorf("page: %d is invalid must be at least 1", request.Page)
+	}
+	if request.PerPage < 1 || request.PerPage > maxPerPage {
+		return request, fmt.Errorf("per_page: %d is invalid must be between 1 and %d", request.PerPage, maxPerPage)
+	}
+	return request, nil
+}
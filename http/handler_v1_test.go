@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestV1(t *testing.T) {
+	cc, err := cache.NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feeds := make([]model.NewsFeed, 45)
+	for i := range feeds {
+		feeds[i] = model.NewsFeed{Title: fmt.Sprintf("News %d", i), Link: fmt.Sprintf("https://www.bbc.co.uk/news/%d", i)}
+	}
+	news := new(MockNewsService)
+	news.On("ListNews", []string{"general"}).Return(newsSvc.ListNewsResponse{
+		NewsFeeds: feeds,
+		Sources:   []newsSvc.SourceStatus{{URL: "http://feeds.bbci.co.uk/news/rss.xml", Provider: model.NewsProviderBBC, State: newsSvc.SourceStateOK}},
+	}, nil)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/gone").Return(model.Article{}, &newsSvc.UpstreamError{
+		Kind:       newsSvc.UpstreamGone,
+		URL:        "https://www.bbc.co.uk/news/gone",
+		StatusCode: http.StatusGone,
+	})
+
+	s := NewService(logrus.New(), news, config.Config{CacheNewsTTL: time.Minute}, cc, nil)
+	s.router = chi.NewRouter()
+	s.routes()
+
+	testCases := []struct {
+		name       string
+		target     string
+		status     int
+		titles     []string
+		pagination Pagination
+		errorCode  string
+		deprecated bool
+	}{
+		{
+			name:       "FirstPage",
+			target:     "/v1/news?per_page=2",
+			status:     http.StatusOK,
+			titles:     []string{"News 0", "News 1"},
+			pagination: Pagination{Page: 1, PerPage: 2, Total: 45, TotalPages: 23},
+		},
+		{
+			name:       "LastPage",
+			target:     "/v1/news?page=3",
+			status:     http.StatusOK,
+			titles:     []string{"News 40", "News 41", "News 42", "News 43", "News 44"},
+			pagination: Pagination{Page: 3, PerPage: 20, Total: 45, TotalPages: 3},
+		},
+		{
+			name:       "PastLastPage",
+			target:     "/v1/news?page=4",
+			status:     http.StatusOK,
+			titles:     []string{},
+			pagination: Pagination{Page: 4, PerPage: 20, Total: 45, TotalPages: 3},
+		},
+		{
+			name:      "InvalidPerPage",
+			target:    "/v1/news?per_page=101",
+			status:    http.StatusBadRequest,
+			errorCode: "invalid_argument",
+		},
+		{
+			name:      "UpstreamError",
+			target:    "/v1/articles?url=https://www.bbc.co.uk/news/gone",
+			status:    http.StatusNotFound,
+			errorCode: "not_found",
+		},
+		{
+			name:       "DeprecatedRoute",
+			target:     "/news",
+			status:     http.StatusOK,
+			deprecated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			assert.Equal(t, tc.status, w.Code)
+
+			if tc.deprecated {
+				assert.Equal(t, "@1792281600", w.Header().Get("Deprecation"))
+				assert.Equal(t, `</v1/news>; rel="successor-version"`, w.Header().Get("Link"))
+				return
+			}
+			assert.Empty(t, w.Header().Get("Deprecation"))
+
+			var response struct {
+				Data   []News     `json:"data"`
+				Meta   *newsMeta  `json:"meta"`
+				Errors []APIError `json:"errors"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			if tc.errorCode != "" {
+				assert.Nil(t, response.Data)
+				assert.Len(t, response.Errors, 1)
+				assert.Equal(t, tc.errorCode, response.Errors[0].Code)
+				return
+			}
+
+			titles := make([]string, len(response.Data))
+			for i, news := range response.Data {
+				titles[i] = news.Title
+			}
+			assert.Equal(t, tc.titles, titles)
+			assert.Equal(t, tc.pagination, response.Meta.Pagination)
+			assert.Len(t, response.Meta.Sources, 1)
+			assert.Empty(t, response.Errors)
+		})
+	}
+}
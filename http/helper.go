@@ -4,44 +4,76 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	newsSvc "github.com/fir1/news/internal/news/service"
+	"fmt"
+	"github.com/fir1/news/pkg/cache"
 	"github.com/go-playground/form/v4"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 /*
 Don’t have to repeat yourself every time you respond to user, instead you can use some helper functions.
 */
 func (s *Service) respond(w http.ResponseWriter, data interface{}, status int) {
-	var respData interface{}
-	switch v := data.(type) {
-	case nil:
-	case newsSvc.ErrArgument:
-		status = http.StatusBadRequest
-		if v.Err != nil {
-			respData = v.Err.Error()
-		}
-	case error:
-		if http.StatusText(status) == "" {
-			status = http.StatusInternalServerError
-		}
-	default:
-		respData = data
+	if data == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Errorf("response encode error: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 
+	// headers written after WriteHeader are not sent
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
+	_, err = w.Write(append(body, '\n'))
+	if err != nil {
+		s.logger.Errorf("response write error: %v", err)
+	}
+}
+
+// respondEncoded writes JSON which is already encoded, e.g. a gzipped cache entry.
+func (s *Service) respondEncoded(w http.ResponseWriter, encoded cache.Encoded, status int) {
 	w.Header().Set("Content-Type", "application/json")
+	if encoded.Encoding != "" {
+		w.Header().Set("Content-Encoding", encoded.Encoding)
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(encoded.Body)))
+	w.WriteHeader(status)
 
-	if respData != nil {
-		err := json.NewEncoder(w).Encode(respData)
-		if err != nil {
-			http.Error(w, "Could not encode in json", http.StatusInternalServerError)
-			return
-		}
+	_, err := w.Write(encoded.Body)
+	if err != nil {
+		s.logger.Errorf("response write error: %v", err)
+	}
+}
+
+// setCacheHeaders tells the client whether the response came from the cache and how old it is,
+// browsers and CDNs may keep it for as long as it stays fresh in our cache.
+func (s *Service) setCacheHeaders(w http.ResponseWriter, meta cache.Meta) {
+	w.Header().Set("X-Cache", string(meta.Status))
+	if meta.Status != cache.StatusMiss {
+		w.Header().Set("Age", strconv.Itoa(int(meta.Age.Seconds())))
 	}
+	w.Header().Set("Last-Modified", time.Now().Add(-meta.Age).UTC().Format(http.TimeFormat))
+
+	maxAge := int(meta.FreshFor.Seconds())
+	if maxAge <= 0 {
+		// stale values and values which were not cached are revalidated with every request
+		w.Header().Set("Cache-Control", "no-cache")
+		return
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d", maxAge, int(s.config.CacheMaxStale.Seconds())))
 }
 
 // it does not read to the memory, instead it will read it to the given 'v' interface.
@@ -81,11 +113,99 @@ func parseQueryParamsToStruct(r *http.Request, strType interface{}) error {
 	if err != nil {
 		return err
 	}
+	return decodeQueryValues(r.Form, strType)
+}
 
+func decodeQueryValues(values url.Values, strType interface{}) error {
 	initOnce.Do(func() {
 		decoder = form.NewDecoder()
 	})
 
-	err = decoder.Decode(&strType, r.Form)
-	return err
+	return decoder.Decode(&strType, values)
+}
+
+// prefersJSON reports whether the Accept header of the request ranks `application/json`
+// above `text/html`, browsers keep getting HTML while API clients can ask for JSON.
+func prefersJSON(r *http.Request) bool {
+	jsonQuality, htmlQuality := -1.0, -1.0
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case "application/json":
+			if quality > jsonQuality {
+				jsonQuality = quality
+			}
+		case "text/html":
+			if quality > htmlQuality {
+				htmlQuality = quality
+			}
+		}
+	}
+	return jsonQuality > 0 && jsonQuality > htmlQuality
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header of the request allows the
+// content coding with a quality above zero.
+func acceptsEncoding(r *http.Request, coding string) bool {
+	return encodingQuality(r, coding) > 0
+}
+
+// encodingQuality returns the quality the Accept-Encoding header of the request gives the
+// content coding, either by name or by `*`, and zero when it is not accepted.
+func encodingQuality(r *http.Request, coding string) float64 {
+	quality, wildcard := -1.0, 0.0
+	for _, accepted := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(accepted), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			q, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case coding:
+			quality = q
+		case "*":
+			wildcard = q
+		}
+	}
+
+	if quality < 0 {
+		return wildcard
+	}
+	return quality
+}
+
+// addVary adds field to the Vary header unless it is listed already.
+func addVary(header http.Header, field string) {
+	for _, value := range header.Values("Vary") {
+		for _, listed := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(listed), field) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", field)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
 }
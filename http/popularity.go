@@ -0,0 +1,83 @@
+package http
+
+import (
+	"sort"
+	"sync"
+)
+
+// maxPopularKeys bounds the memory of a popularity tracker, new keys are ignored while
+// it is full until decay drops the keys which are no longer requested.
+const maxPopularKeys = 1000
+
+// popularity counts the recent requests per cache key, the cache warmer refreshes the
+// most requested news lists and articles.
+type popularity[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*popularEntry[T]
+}
+
+type popularEntry[T any] struct {
+	value T
+	count int
+}
+
+func newPopularity[T any]() *popularity[T] {
+	return &popularity[T]{entries: map[string]*popularEntry[T]{}}
+}
+
+// record counts a request of key, value is what the warmer needs to load the entry again.
+func (p *popularity[T]) record(key string, value T) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.entries[key]
+	if !ok {
+		if len(p.entries) >= maxPopularKeys {
+			return
+		}
+		entry = &popularEntry[T]{value: value}
+		p.entries[key] = entry
+	}
+	entry.count++
+}
+
+// top returns the values of the n most requested keys, the most requested first.
+func (p *popularity[T]) top(n int) []T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := make([]string, 0, len(p.entries))
+	for key := range p.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := p.entries[keys[i]].count, p.entries[keys[j]].count
+		if ci != cj {
+			return ci > cj
+		}
+		return keys[i] < keys[j]
+	})
+
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	values := make([]T, len(keys))
+	for i, key := range keys {
+		values[i] = p.entries[key].value
+	}
+	return values
+}
+
+// decay halves the counts, so requests of the last runs of the warmer outweigh older
+// ones, and forgets the keys which were not requested for a while.
+func (p *popularity[T]) decay() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, entry := range p.entries {
+		entry.count /= 2
+		if entry.count == 0 {
+			delete(p.entries, key)
+		}
+	}
+}
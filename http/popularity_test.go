@@ -0,0 +1,25 @@
+package http
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPopularity(t *testing.T) {
+	p := newPopularity[string]()
+	for i := 0; i < 3; i++ {
+		p.record("article:b", "b")
+	}
+	p.record("article:a", "a")
+	p.record("article:c", "c")
+
+	assert.Equal(t, []string{"b", "a"}, p.top(2))
+	assert.Equal(t, []string{"b", "a", "c"}, p.top(10))
+
+	// keys requested once are forgotten, the others count half
+	p.decay()
+	assert.Equal(t, []string{"b"}, p.top(10))
+	p.record("article:a", "a")
+	p.record("article:a", "a")
+	assert.Equal(t, []string{"a", "b"}, p.top(10))
+}
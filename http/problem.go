@@ -0,0 +1,108 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+const problemContentType = "application/problem+json"
+
+// problemTypeBase is the URI reference the error code of a problem is appended to,
+// problems which are not an error of the news service have the type about:blank.
+const problemTypeBase = "/problems/"
+
+// statusClientClosedRequest is reported when the client went away before the response
+// was ready, it is not defined by RFC 9110 but commonly used by proxies.
+const statusClientClosedRequest = 499
+
+// problem is an RFC 7807 problem details object.
+type problem struct {
+	// URI reference identifying the problem type, one-of: /problems/invalid_argument,
+	// /problems/not_found, /problems/upstream_unavailable, /problems/upstream_rate_limited,
+	// /problems/timeout, /problems/canceled, /problems/internal or about:blank
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// path of the request the problem occurred in
+	Instance string `json:"instance,omitempty"`
+} // @name Problem
+
+var errorCodeTitles = map[newsSvc.ErrorCode]string{
+	newsSvc.CodeInvalidArgument:     "Invalid argument",
+	newsSvc.CodeNotFound:            "Not found",
+	newsSvc.CodeUpstreamUnavailable: "Upstream unavailable",
+	newsSvc.CodeUpstreamRateLimited: "Upstream rate limited",
+	newsSvc.CodeTimeout:             "Upstream timeout",
+	newsSvc.CodeCanceled:            "Request canceled",
+	newsSvc.CodeInternal:            "Internal error",
+}
+
+// respondProblem reports a problem which is not an error of the news service, its type is
+// about:blank and its title the text of the status code.
+func (s *Service) respondProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	s.writeProblem(w, problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	})
+}
+
+// respondServiceError reports an error of the news service as a problem typed by its
+// error code, the Retry-After header the upstream asked for is passed on.
+func (s *Service) respondServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	code := newsSvc.Code(err)
+	s.writeProblem(w, problem{
+		Type:     problemTypeBase + string(code),
+		Title:    errorCodeTitles[code],
+		Status:   serviceErrorStatus(w, err),
+		Detail:   err.Error(),
+		Instance: r.URL.Path,
+	})
+}
+
+func (s *Service) writeProblem(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", problemContentType)
+	// the status and the detail change with every request
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(p.Status)
+
+	err := json.NewEncoder(w).Encode(p)
+	if err != nil {
+		s.logger.Errorf("problem write error: %v", err)
+	}
+}
+
+// serviceErrorStatus returns the status code an error of the news service is reported
+// with and sets the Retry-After header the upstream asked for.
+func serviceErrorStatus(w http.ResponseWriter, err error) int {
+	var upstreamErr *newsSvc.UpstreamError
+	if errors.As(err, &upstreamErr) && upstreamErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(upstreamErr.RetryAfter.Seconds()))))
+	}
+	return errorCodeStatus(newsSvc.Code(err))
+}
+
+func errorCodeStatus(code newsSvc.ErrorCode) int {
+	switch code {
+	case newsSvc.CodeInvalidArgument:
+		return http.StatusBadRequest
+	case newsSvc.CodeNotFound:
+		return http.StatusNotFound
+	case newsSvc.CodeUpstreamRateLimited:
+		return http.StatusTooManyRequests
+	case newsSvc.CodeTimeout:
+		return http.StatusGatewayTimeout
+	case newsSvc.CodeCanceled:
+		return statusClientClosedRequest
+	case newsSvc.CodeInternal:
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadGateway
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/internal/news/model"
+	newsSvc "github.com/fir1/news/internal/news/service"
+	"github.com/fir1/news/pkg/cache"
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProblemResponses(t *testing.T) {
+	cc, err := cache.NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	news := new(MockNewsService)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/missing").Return(model.Article{}, &newsSvc.UpstreamError{
+		Kind:       newsSvc.UpstreamNotFound,
+		URL:        "https://www.bbc.co.uk/news/missing",
+		StatusCode: http.StatusNotFound,
+	})
+	news.On("GetArticle", "https://www.bbc.co.uk/news/busy").Return(model.Article{}, &newsSvc.RetriableError{
+		Err: &newsSvc.UpstreamError{
+			Kind:       newsSvc.UpstreamRateLimited,
+			URL:        "https://www.bbc.co.uk/news/busy",
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: 1500 * time.Millisecond,
+		},
+		RetryAfter: 1500 * time.Millisecond,
+	})
+	news.On("GetArticle", "https://www.bbc.co.uk/news/broken").Return(model.Article{}, &newsSvc.UpstreamError{
+		Kind:       newsSvc.UpstreamServerError,
+		URL:        "https://www.bbc.co.uk/news/broken",
+		StatusCode: http.StatusInternalServerError,
+	})
+	news.On("GetArticle", "https://www.bbc.co.uk/news/slow").Return(model.Article{}, context.DeadlineExceeded)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/left").Return(model.Article{}, context.Canceled)
+	news.On("GetArticle", "https://www.bbc.co.uk/news/bug").Return(model.Article{}, errors.New("unexpected failure"))
+
+	s := NewService(logrus.New(), news, config.Config{CacheArticleTTL: time.Minute}, cc, nil)
+	s.router = chi.NewRouter()
+	s.routes()
+
+	testCases := []struct {
+		name       string
+		target     string
+		status     int
+		problem    problem
+		retryAfter string
+	}{
+		{
+			name:   "InvalidArgument",
+			target: "/article?url=https://www.bbc.co.uk/news/1&format=pdf",
+			status: http.StatusBadRequest,
+			problem: problem{
+				Type:   "/problems/invalid_argument",
+				Title:  "Invalid argument",
+				Detail: "invalid argument: format: pdf is invalid must be `html`, `markdown`, `text`",
+			},
+		},
+		{
+			name:   "NotFound",
+			target: "/article?url=https://www.bbc.co.uk/news/missing",
+			status: http.StatusNotFound,
+			problem: problem{
+				Type:   "/problems/not_found",
+				Title:  "Not found",
+				Detail: "https://www.bbc.co.uk/news/missing: not_found, upstream responded with status 404",
+			},
+		},
+		{
+			name:   "UpstreamRateLimited",
+			target: "/article?url=https://www.bbc.co.uk/news/busy",
+			status: http.StatusTooManyRequests,
+			problem: problem{
+				Type:   "/problems/upstream_rate_limited",
+				Title:  "Upstream rate limited",
+				Detail: "https://www.bbc.co.uk/news/busy: rate_limited, upstream responded with status 429 (retry after 1.5s) (retry after 1.5s)",
+			},
+			retryAfter: "2",
+		},
+		{
+			name:   "UpstreamUnavailable",
+			target: "/article?url=https://www.bbc.co.uk/news/broken",
+			status: http.StatusBadGateway,
+			problem: problem{
+				Type:   "/problems/upstream_unavailable",
+				Title:  "Upstream unavailable",
+				Detail: "https://www.bbc.co.uk/news/broken: server_error, upstream responded with status 500",
+			},
+		},
+		{
+			name:   "Timeout",
+			target: "/article?url=https://www.bbc.co.uk/news/slow",
+			status: http.StatusGatewayTimeout,
+			problem: problem{
+				Type:   "/problems/timeout",
+				Title:  "Upstream timeout",
+				Detail: "context deadline exceeded",
+			},
+		},
+		{
+			name:   "Canceled",
+			target: "/article?url=https://www.bbc.co.uk/news/left",
+			status: statusClientClosedRequest,
+			problem: problem{
+				Type:   "/problems/canceled",
+				Title:  "Request canceled",
+				Detail: "context canceled",
+			},
+		},
+		{
+			name:   "Internal",
+			target: "/article?url=https://www.bbc.co.uk/news/bug",
+			status: http.StatusInternalServerError,
+			problem: problem{
+				Type:   "/problems/internal",
+				Title:  "Internal error",
+				Detail: "unexpected failure",
+			},
+		},
+		{
+			name:   "AdminDisabled",
+			target: "/admin/cache/stats",
+			status: http.StatusForbidden,
+			problem: problem{
+				Type:   "about:blank",
+				Title:  "Forbidden",
+				Detail: "admin endpoints are disabled, set ADMIN_TOKEN to enable them",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			s.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.retryAfter, w.Header().Get("Retry-After"))
+
+			var body problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			tc.problem.Status = tc.status
+			tc.problem.Instance = r.URL.Path
+			assert.Equal(t, tc.problem, body)
+		})
+	}
+}
@@ -1,7 +1,34 @@
 package http
 
+import "github.com/go-chi/chi/v5"
+
 func (s *Service) routes() {
 	s.router.Get("/health", s.GetHealth)
-	s.router.Get("/news", s.listNews)
-	s.router.Get("/article", s.getArticle)
+
+	// the unversioned routes answer like before until their clients moved to /v1, the
+	// article page is meant for browsers and stays
+	s.router.Group(func(r chi.Router) {
+		r.Use(conditionalGET)
+		r.With(deprecated("/v1/news")).Get("/news", s.listNews)
+		r.Get("/article", s.getArticle)
+		r.With(deprecated("/v1/articles")).Get("/articles", s.getArticleJSON)
+	})
+	// the breakers are keyed by the feed URLs clients ask for and report upstream errors
+	s.router.With(s.requireAdmin, deprecated("/v1/diagnostics/circuit-breakers")).Get("/diagnostics/circuit-breakers", s.listCircuitBreakers)
+
+	s.router.Route("/v1", func(r chi.Router) {
+		r.With(conditionalGET).Get("/news", s.listNewsV1)
+		r.With(conditionalGET).Get("/articles", s.getArticleV1)
+		r.With(s.requireAdmin).Get("/diagnostics/circuit-breakers", s.listCircuitBreakersV1)
+	})
+
+	s.router.Get("/graphql", s.queryGraphQL)
+	s.router.Post("/graphql", s.queryGraphQL)
+
+	s.router.Route("/admin", func(r chi.Router) {
+		r.Use(s.requireAdmin)
+		r.Get("/cache/stats", s.getCacheStats)
+		r.Delete("/cache/entries", s.deleteCacheEntries)
+		r.Post("/cache/reset", s.resetCache)
+	})
 }
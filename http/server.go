@@ -38,7 +38,7 @@ func (s *Service) Run(stop chan struct{}) error {
 	s.router.Use(
 		cors.Handler(cors.Options{
 			AllowedOrigins:     []string{"*"}, //TODO: must be changed to allow only prod, dev hosts.
-			AllowedMethods:     []string{"GET", "POST", "HEAD", "PATCH", "OPTIONS", "GET", "PUT"},
+			AllowedMethods:     []string{"GET", "POST", "HEAD", "PATCH", "OPTIONS", "GET", "PUT", "DELETE"},
 			AllowedHeaders:     []string{"*"},
 			ExposedHeaders:     nil,
 			AllowCredentials:   true,
@@ -48,6 +48,9 @@ func (s *Service) Run(stop chan struct{}) error {
 		}),
 		middleware.Logger,
 	)
+	if s.config.ResponseCompressionEnabled {
+		s.router.Use(compress(s.config.ResponseCompressionMinSize))
+	}
 
 	docs.SwaggerInfo.Host = fmt.Sprintf("%s:%d", stripProtocol(s.config.ServerHostName), s.config.LoadBalancerHostPort)
 
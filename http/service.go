@@ -2,9 +2,12 @@ package http
 
 import (
 	"github.com/fir1/news/config"
+	"github.com/fir1/news/internal/news/model"
 	newsSvc "github.com/fir1/news/internal/news/service"
 	"github.com/fir1/news/pkg/cache"
+	"github.com/fir1/news/pkg/circuitbreaker"
 	"github.com/go-chi/chi/v5"
+	"github.com/graph-gophers/graphql-go"
 
 	"github.com/sirupsen/logrus"
 )
@@ -17,17 +20,35 @@ type Service struct {
 	newsService       newsSvc.NewsInterface
 	config            config.Config
 	cacheClient       cache.CacheClientInterface
+	newsCache         cache.Typed[listNewsResponse]
+	articleCache      cache.Typed[model.Article]
+	// the most requested news lists and articles, refreshed by the cache warmer
+	popularNews     *popularity[listNewsRequest]
+	popularArticles *popularity[string]
+	breakers        *circuitbreaker.Registry
+	graphqlSchema   *graphql.Schema
 }
 
 func NewService(logger *logrus.Logger,
 	newsSvc newsSvc.NewsInterface,
 	cnf config.Config,
 	cc cache.CacheClientInterface,
+	breakers *circuitbreaker.Registry,
 ) *Service {
-	return &Service{
+	s := &Service{
 		logger:      logger,
 		newsService: newsSvc,
 		config:      cnf,
 		cacheClient: cc,
+		newsCache: cache.NewTyped[listNewsResponse](cc, cnf.CacheNewsTTL).
+			WithMaxStale(cnf.CacheMaxStale).
+			WithCacheable(allSourcesOK),
+		articleCache: cache.NewTyped[model.Article](cc, cnf.CacheArticleTTL).
+			WithMaxStale(cnf.CacheMaxStale),
+		popularNews:     newPopularity[listNewsRequest](),
+		popularArticles: newPopularity[string](),
+		breakers:        breakers,
 	}
+	s.graphqlSchema = newGraphQLSchema(s)
+	return s
 }
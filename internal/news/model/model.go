@@ -47,5 +47,27 @@ type Article struct {
 	Title       string
 	Description string
 	Content     string
+	// ContentHTML keeps the markup of the article body so it can be converted to other formats
+	ContentHTML string
 	Link        string
+	// metadata extracted from OpenGraph, Twitter Card and schema.org JSON-LD markup
+	CanonicalURL string
+	ImageURL     string
+	SiteName     string
+	PublishedAt  time.Time
+	Author       string
+	Section      string
+	Keywords     []string
+	// WordCount and ReadingTimeMinutes are computed from the extracted Content
+	WordCount          int
+	ReadingTimeMinutes int
+}
+
+// SourceURL is the canonical URL of the article, or the URL it was extracted from
+// when it has none.
+func (a Article) SourceURL() string {
+	if a.CanonicalURL != "" {
+		return a.CanonicalURL
+	}
+	return a.Link
 }
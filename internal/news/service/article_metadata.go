@@ -0,0 +1,277 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/PuerkitoBio/goquery"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// articleMetadata holds the values found in the <head> of an article page.
+// Every field is collected from OpenGraph, Twitter Card, plain <meta> tags and
+// schema.org JSON-LD, the first non-empty source wins.
+type articleMetadata struct {
+	Title        string
+	Description  string
+	CanonicalURL string
+	ImageURL     string
+	SiteName     string
+	PublishedAt  time.Time
+	Author       string
+	Section      string
+	Keywords     []string
+}
+
+// jsonLDArticleTypes are the schema.org types we read article metadata from.
+var jsonLDArticleTypes = map[string]bool{
+	"Article":              true,
+	"NewsArticle":          true,
+	"ReportageNewsArticle": true,
+	"AnalysisNewsArticle":  true,
+	"BlogPosting":          true,
+}
+
+func extractArticleMetadata(doc *goquery.Document, base *url.URL) articleMetadata {
+	ld := findJSONLDArticle(doc)
+
+	metadata := articleMetadata{
+		Title: firstNonEmpty(
+			metaContent(doc, "property", "og:title"),
+			metaContent(doc, "name", "twitter:title"),
+			jsonLDString(ld["headline"]),
+		),
+		Description: firstNonEmpty(
+			metaContent(doc, "name", "description"),
+			metaContent(doc, "property", "og:description"),
+			metaContent(doc, "name", "twitter:description"),
+			jsonLDString(ld["description"]),
+		),
+		CanonicalURL: resolveURL(base, firstNonEmpty(
+			attrValue(doc, "link[rel=canonical]", "href"),
+			metaContent(doc, "property", "og:url"),
+			jsonLDString(ld["url"]),
+			jsonLDString(ld["mainEntityOfPage"]),
+		)),
+		ImageURL: resolveURL(base, firstNonEmpty(
+			metaContent(doc, "property", "og:image:secure_url"),
+			metaContent(doc, "property", "og:image"),
+			metaContent(doc, "name", "twitter:image"),
+			metaContent(doc, "name", "twitter:image:src"),
+			jsonLDString(ld["image"]),
+		)),
+		SiteName: firstNonEmpty(
+			metaContent(doc, "property", "og:site_name"),
+			metaContent(doc, "name", "application-name"),
+			jsonLDNames(ld["publisher"]),
+		),
+		Author: firstNonEmpty(
+			jsonLDNames(ld["author"]),
+			metaContent(doc, "name", "author"),
+			notURL(metaContent(doc, "property", "article:author")),
+			strings.TrimSpace(doc.Find("[rel=author]").First().Text()),
+		),
+		Section: firstNonEmpty(
+			metaContent(doc, "property", "article:section"),
+			jsonLDString(ld["articleSection"]),
+		),
+	}
+
+	publishedAt := firstNonEmpty(
+		metaContent(doc, "property", "article:published_time"),
+		jsonLDString(ld["datePublished"]),
+		metaContent(doc, "itemprop", "datePublished"),
+		attrValue(doc, "time[datetime]", "datetime"),
+	)
+	if publishedAt != "" {
+		metadata.PublishedAt, _ = parseMetadataTime(publishedAt)
+	}
+
+	var keywords []string
+	doc.Find(`meta[property="article:tag"]`).Each(func(i int, s *goquery.Selection) {
+		keywords = append(keywords, s.AttrOr("content", ""))
+	})
+	keywords = append(keywords, splitKeywords(metaContent(doc, "name", "news_keywords"))...)
+	keywords = append(keywords, splitKeywords(metaContent(doc, "name", "keywords"))...)
+	keywords = append(keywords, jsonLDKeywords(ld["keywords"])...)
+	metadata.Keywords = uniqueNonEmpty(keywords)
+
+	return metadata
+}
+
+func metaContent(doc *goquery.Document, attr, value string) string {
+	return attrValue(doc, `meta[`+attr+`="`+value+`"]`, "content")
+}
+
+func attrValue(doc *goquery.Document, selector, attr string) string {
+	value, _ := doc.Find(selector).First().Attr(attr)
+	return strings.TrimSpace(value)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func notURL(value string) string {
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return ""
+	}
+	return value
+}
+
+// resolveURL turns relative references such as "/images/hero.jpg" into absolute
+// URLs using the address of the article page.
+func resolveURL(base *url.URL, ref string) string {
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+func splitKeywords(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func uniqueNonEmpty(values []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+func parseMetadataTime(input string) (time.Time, error) {
+	layouts := []string{
+		time.RFC3339Nano,
+		time.RFC3339,
+		"2006-01-02T15:04:05Z0700",
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02T15:04",
+		time.RFC1123Z,
+		time.RFC1123,
+	}
+
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, input)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return parseTimeFromString(input)
+}
+
+// findJSONLDArticle returns the first schema.org article object found in the
+// page's <script type="application/ld+json"> blocks, looking into arrays and @graph.
+func findJSONLDArticle(doc *goquery.Document) map[string]interface{} {
+	var article map[string]interface{}
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		var data interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &data); err != nil {
+			return true
+		}
+		article = findJSONLDArticleNode(data)
+		return article == nil
+	})
+	return article
+}
+
+func findJSONLDArticleNode(data interface{}) map[string]interface{} {
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if article := findJSONLDArticleNode(item); article != nil {
+				return article
+			}
+		}
+	case map[string]interface{}:
+		if isJSONLDArticle(v["@type"]) {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findJSONLDArticleNode(graph)
+		}
+	}
+	return nil
+}
+
+func isJSONLDArticle(t interface{}) bool {
+	switch v := t.(type) {
+	case string:
+		return jsonLDArticleTypes[v]
+	case []interface{}:
+		for _, item := range v {
+			if isJSONLDArticle(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDString reads a value which may be a plain string, an object with a
+// `url`, `name` or `@id` field or a list of those, and returns the first one.
+func jsonLDString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		for _, item := range v {
+			if s := jsonLDString(item); s != "" {
+				return s
+			}
+		}
+	case map[string]interface{}:
+		return firstNonEmpty(jsonLDString(v["url"]), jsonLDString(v["name"]), jsonLDString(v["@id"]))
+	}
+	return ""
+}
+
+// jsonLDNames joins the names of every person or organization listed in value.
+func jsonLDNames(value interface{}) string {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		names = append(names, v)
+	case map[string]interface{}:
+		names = append(names, jsonLDString(v["name"]))
+	case []interface{}:
+		for _, item := range v {
+			names = append(names, jsonLDNames(item))
+		}
+	}
+	return strings.Join(uniqueNonEmpty(names), ", ")
+}
+
+func jsonLDKeywords(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return splitKeywords(v)
+	case []interface{}:
+		var keywords []string
+		for _, item := range v {
+			keywords = append(keywords, jsonLDKeywords(item)...)
+		}
+		return keywords
+	}
+	return nil
+}
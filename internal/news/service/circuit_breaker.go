@@ -0,0 +1,15 @@
+package service
+
+import (
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/pkg/circuitbreaker"
+)
+
+// NewCircuitBreakers returns the registry holding one circuit breaker per feed URL.
+func NewCircuitBreakers(cnf config.Config) *circuitbreaker.Registry {
+	return circuitbreaker.NewRegistry(circuitbreaker.Config{
+		FailureThreshold:    cnf.CircuitBreakerFailureThreshold,
+		OpenTimeout:         cnf.CircuitBreakerOpenTimeout,
+		HalfOpenMaxRequests: cnf.CircuitBreakerHalfOpenMaxRequests,
+	})
+}
@@ -1,7 +1,15 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/fir1/news/pkg/circuitbreaker"
+	"github.com/fir1/news/pkg/ratelimit"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,6 +24,10 @@ func (e *RetriableError) Error() string {
 	return fmt.Sprintf("%s (retry after %v)", e.Err.Error(), e.RetryAfter)
 }
 
+func (e *RetriableError) Unwrap() error {
+	return e.Err
+}
+
 // ErrArgument is a custom error that contains an error message for validation
 type ErrArgument struct {
 	Err error
@@ -25,3 +37,181 @@ type ErrArgument struct {
 func (e ErrArgument) Error() string {
 	return fmt.Sprintf("invalid argument: %s", e.Err.Error())
 }
+
+// ErrorCode classifies the errors of the service independent of the transport, the API
+// maps every code to its status code.
+type ErrorCode string
+
+const (
+	CodeInvalidArgument ErrorCode = "invalid_argument"
+	// CodeNotFound is reported when the feed or the article does not exist (anymore)
+	CodeNotFound ErrorCode = "not_found"
+	// CodeUpstreamUnavailable is reported when the upstream failed, answered with a body
+	// we can not read, could not be reached or its circuit breaker is open
+	CodeUpstreamUnavailable ErrorCode = "upstream_unavailable"
+	// CodeUpstreamRateLimited is reported when the upstream or our limit for it asks to slow down
+	CodeUpstreamRateLimited ErrorCode = "upstream_rate_limited"
+	// CodeTimeout is reported when the upstream did not answer before the deadline
+	CodeTimeout ErrorCode = "timeout"
+	// CodeCanceled is reported when the caller went away before the upstream answered
+	CodeCanceled ErrorCode = "canceled"
+	// CodeInternal is reported for every error which did not come from fetching the upstream
+	CodeInternal ErrorCode = "internal"
+)
+
+// Code returns the ErrorCode of an error returned by the service, errors which did not
+// come from fetching the upstream are reported as internal.
+func Code(err error) ErrorCode {
+	var errArgument ErrArgument
+	if errors.As(err, &errArgument) {
+		return CodeInvalidArgument
+	}
+
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) {
+		switch upstreamErr.Kind {
+		case UpstreamNotFound, UpstreamGone:
+			return CodeNotFound
+		case UpstreamRateLimited:
+			return CodeUpstreamRateLimited
+		}
+		return CodeUpstreamUnavailable
+	}
+
+	if errors.Is(err, ratelimit.ErrDeadlineExceeded) {
+		return CodeUpstreamRateLimited
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return CodeCanceled
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return CodeTimeout
+	}
+
+	// the upstream could not be reached, e.g. the connection was refused, its response
+	// was cut off or its circuit breaker is open
+	var readErr *upstreamReadError
+	if errors.As(err, &netErr) || errors.As(err, &readErr) || errors.Is(err, circuitbreaker.ErrOpen) {
+		return CodeUpstreamUnavailable
+	}
+	return CodeInternal
+}
+
+// upstreamReadError is returned when the response of the upstream could not be read,
+// e.g. because it was larger than allowed or its compression is broken.
+type upstreamReadError struct {
+	url string
+	err error
+}
+
+func (e *upstreamReadError) Error() string {
+	return fmt.Sprintf("%s: reading the response: %s", e.url, e.err.Error())
+}
+
+func (e *upstreamReadError) Unwrap() error {
+	return e.err
+}
+
+type UpstreamErrorKind string
+
+const (
+	UpstreamNotFound    UpstreamErrorKind = "not_found"
+	UpstreamGone        UpstreamErrorKind = "gone"
+	UpstreamRateLimited UpstreamErrorKind = "rate_limited"
+	// UpstreamClientError is any other 4xx response
+	UpstreamClientError UpstreamErrorKind = "client_error"
+	UpstreamServerError UpstreamErrorKind = "server_error"
+	// UpstreamUnexpectedStatus is any other status than 200 below 400, e.g. a 204 without
+	// a body or a 206 with only part of it
+	UpstreamUnexpectedStatus UpstreamErrorKind = "unexpected_status"
+	// UpstreamInvalidFeed is reported for responses which are not a readable RSS feed
+	UpstreamInvalidFeed UpstreamErrorKind = "invalid_feed"
+)
+
+// UpstreamError is returned when a feed or article could be requested but the upstream
+// answered with an error status or a body we can not read
+type UpstreamError struct {
+	Kind       UpstreamErrorKind
+	URL        string
+	StatusCode int
+	// RetryAfter is the wait requested by a rate limited upstream, zero when it sent none
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *UpstreamError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%s: %s: %s", e.URL, e.Kind, e.Err.Error())
+	case e.RetryAfter > 0:
+		return fmt.Sprintf("%s: %s, upstream responded with status %d (retry after %v)", e.URL, e.Kind, e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s: %s, upstream responded with status %d", e.URL, e.Kind, e.StatusCode)
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// Retriable reports whether asking the upstream again may succeed.
+func (e *UpstreamError) Retriable() bool {
+	return e.Kind == UpstreamRateLimited || e.Kind == UpstreamServerError
+}
+
+// isReadableStatus reports whether the body of a response is read, feeds and articles are
+// only read from a complete 200 response.
+func isReadableStatus(statusCode int) bool {
+	return statusCode == http.StatusOK
+}
+
+// newUpstreamStatusError classifies a response with an unexpected status code,
+// the Retry-After header of a 429 response is parsed relative to now.
+func newUpstreamStatusError(url string, resp *http.Response, now time.Time) *UpstreamError {
+	err := &UpstreamError{URL: url, StatusCode: resp.StatusCode}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		err.Kind = UpstreamNotFound
+	case resp.StatusCode == http.StatusGone:
+		err.Kind = UpstreamGone
+	case resp.StatusCode == http.StatusTooManyRequests:
+		err.Kind = UpstreamRateLimited
+		err.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	case resp.StatusCode >= 500:
+		err.Kind = UpstreamServerError
+	case resp.StatusCode < 400:
+		err.Kind = UpstreamUnexpectedStatus
+	default:
+		err.Kind = UpstreamClientError
+	}
+	return err
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date,
+// a date in the past results in a zero duration.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 32)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		// the date has a resolution of seconds
+		return wait.Round(time.Second), true
+	}
+	return 0, true
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fir1/news/pkg/circuitbreaker"
+	"github.com/fir1/news/pkg/httpclient"
+	"github.com/fir1/news/pkg/netguard"
+	"github.com/fir1/news/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestCode(t *testing.T) {
+	const feedURL = "http://feeds.bbci.co.uk/news/uk/rss.xml"
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected ErrorCode
+	}{
+		{
+			name:     "InvalidArgument",
+			err:      ErrArgument{Err: errors.New("provider: cnn is invalid must be `sky`, `bbc`")},
+			expected: CodeInvalidArgument,
+		},
+		{
+			name:     "ForbiddenDestination",
+			err:      refusedURLError(fmt.Errorf("%s: %w", feedURL, netguard.ErrForbiddenDestination)),
+			expected: CodeInvalidArgument,
+		},
+		{
+			name:     "UpstreamNotFound",
+			err:      &UpstreamError{Kind: UpstreamNotFound, URL: feedURL, StatusCode: http.StatusNotFound},
+			expected: CodeNotFound,
+		},
+		{
+			name:     "UpstreamGone",
+			err:      &UpstreamError{Kind: UpstreamGone, URL: feedURL, StatusCode: http.StatusGone},
+			expected: CodeNotFound,
+		},
+		{
+			name: "UpstreamRateLimited",
+			err: &RetriableError{
+				Err: &UpstreamError{Kind: UpstreamRateLimited, URL: feedURL, StatusCode: http.StatusTooManyRequests},
+			},
+			expected: CodeUpstreamRateLimited,
+		},
+		{
+			name:     "RateLimiterDeadline",
+			err:      fmt.Errorf("%s: %w", feedURL, ratelimit.ErrDeadlineExceeded),
+			expected: CodeUpstreamRateLimited,
+		},
+		{
+			name:     "UpstreamServerError",
+			err:      &UpstreamError{Kind: UpstreamServerError, URL: feedURL, StatusCode: http.StatusInternalServerError},
+			expected: CodeUpstreamUnavailable,
+		},
+		{
+			name:     "UpstreamInvalidFeed",
+			err:      &UpstreamError{Kind: UpstreamInvalidFeed, URL: feedURL, StatusCode: http.StatusOK, Err: errors.New("EOF")},
+			expected: CodeUpstreamUnavailable,
+		},
+		{
+			name:     "CircuitBreakerOpen",
+			err:      fmt.Errorf("%s: %w", feedURL, circuitbreaker.ErrOpen),
+			expected: CodeUpstreamUnavailable,
+		},
+		{
+			name:     "ContextDeadline",
+			err:      fmt.Errorf("%s: %w", feedURL, context.DeadlineExceeded),
+			expected: CodeTimeout,
+		},
+		{
+			name:     "NetworkTimeout",
+			err:      &url.Error{Op: "Get", URL: feedURL, Err: timeoutError{}},
+			expected: CodeTimeout,
+		},
+		{
+			name:     "ConnectionRefused",
+			err:      &url.Error{Op: "Get", URL: feedURL, Err: errors.New("connection refused")},
+			expected: CodeUpstreamUnavailable,
+		},
+		{
+			name:     "ResponseTooLarge",
+			err:      &upstreamReadError{url: feedURL, err: httpclient.ErrResponseTooLarge},
+			expected: CodeUpstreamUnavailable,
+		},
+		{
+			name:     "Canceled",
+			err:      &url.Error{Op: "Get", URL: feedURL, Err: context.Canceled},
+			expected: CodeCanceled,
+		},
+		{
+			name:     "Internal",
+			err:      errors.New("unexpected failure"),
+			expected: CodeInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Code(tc.err))
+		})
+	}
+}
@@ -3,8 +3,9 @@ package service
 import (
 	"context"
 	"encoding/xml"
+	"errors"
+	"io"
 	"net/http"
-	"strconv"
 	"time"
 )
 
@@ -13,10 +14,13 @@ type NewsFetcher interface {
 }
 
 type RealFetcherService struct {
+	client *http.Client
 }
 
-func NewRealFetcherService() NewsFetcher {
-	return RealFetcherService{}
+func NewRealFetcherService(client *http.Client) NewsFetcher {
+	return RealFetcherService{
+		client: client,
+	}
 }
 
 // FetchNewsFeeds fetches news articles from the given feed URL and returns a slice of NewsFeed objects.
@@ -26,45 +30,44 @@ func (s RealFetcherService) fetchNews(ctx context.Context, feedURL string) (RSS,
 		return RSS{}, err
 	}
 
-	client := http.Client{
-		Timeout: 1 * time.Minute,
-	}
-
-	resp, err := client.Do(request)
+	resp, err := s.client.Do(request)
 	if err != nil {
 		return RSS{}, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		if resp.StatusCode == http.StatusTooManyRequests {
-			// check Retry-After header if it contains seconds to wait for the next retry
-			retryAfter, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 32)
-			if err != nil {
-				return RSS{}, err
-			}
-
-			// the server returns 0 to inform that the operation cannot be retried
-			if retryAfter <= 0 {
-				return RSS{}, err
-			}
-
+	if !isReadableStatus(resp.StatusCode) {
+		upstreamErr := newUpstreamStatusError(feedURL, resp, time.Now())
+		if upstreamErr.RetryAfter > 0 {
+			// follow the recommendation of the server when to ask again
 			return RSS{}, &RetriableError{
-				Err:        err,
-				RetryAfter: time.Duration(retryAfter) * time.Second,
+				Err:        upstreamErr,
+				RetryAfter: upstreamErr.RetryAfter,
 			}
 		}
+		return RSS{}, upstreamErr
 	}
 
 	var result RSS
 	err = xml.NewDecoder(resp.Body).Decode(&result)
 	if err != nil {
-		return RSS{}, err
+		if isInvalidFeed(err) {
+			return RSS{}, &UpstreamError{Kind: UpstreamInvalidFeed, URL: feedURL, StatusCode: resp.StatusCode, Err: err}
+		}
+		return RSS{}, &upstreamReadError{url: feedURL, err: err}
 	}
 
 	return result, nil
 }
 
+// isInvalidFeed reports whether a decoding error was caused by the content of the feed
+// rather than by reading the response.
+func isInvalidFeed(err error) bool {
+	var syntaxErr *xml.SyntaxError
+	var unmarshalErr xml.UnmarshalError
+	return errors.Is(err, io.EOF) || errors.As(err, &syntaxErr) || errors.As(err, &unmarshalErr)
+}
+
 type RSS struct {
 	XMLName xml.Name `xml:"rss"`
 	Channel Channel  `xml:"channel"`
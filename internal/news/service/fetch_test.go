@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/avast/retry-go"
 	"github.com/stretchr/testify/assert"
@@ -153,3 +155,111 @@ func TestFetchNews(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchNewsUpstreamErrors(t *testing.T) {
+	retryAt := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed.xml":
+			w.Write([]byte(`<rss><channel><title>Feed</title></channel></rss>`))
+		case "/html.xml":
+			w.Write([]byte(`<html><body>Not a feed</body></html>`))
+		case "/empty.xml":
+		case "/no-content.xml":
+			w.WriteHeader(http.StatusNoContent)
+		case "/partial.xml":
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte(`<rss><channel><title>Feed</title>`))
+		case "/gone.xml":
+			w.WriteHeader(http.StatusGone)
+		case "/forbidden.xml":
+			w.WriteHeader(http.StatusForbidden)
+		case "/error.xml":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/limited-seconds.xml":
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/limited-date.xml":
+			w.Header().Set("Retry-After", retryAt)
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/limited.xml":
+			w.Header().Set("Retry-After", "soon")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name         string
+		path         string
+		expectedKind UpstreamErrorKind
+		retryAfter   time.Duration
+	}{
+		{name: "Success", path: "/feed.xml"},
+		{name: "NotFound", path: "/missing.xml", expectedKind: UpstreamNotFound},
+		{name: "Gone", path: "/gone.xml", expectedKind: UpstreamGone},
+		{name: "ClientError", path: "/forbidden.xml", expectedKind: UpstreamClientError},
+		{name: "ServerError", path: "/error.xml", expectedKind: UpstreamServerError},
+		{name: "RateLimitedSeconds", path: "/limited-seconds.xml", expectedKind: UpstreamRateLimited, retryAfter: 7 * time.Second},
+		{name: "RateLimitedDate", path: "/limited-date.xml", expectedKind: UpstreamRateLimited, retryAfter: 30 * time.Second},
+		{name: "RateLimitedWithoutRetryAfter", path: "/limited.xml", expectedKind: UpstreamRateLimited},
+		{name: "HTML", path: "/html.xml", expectedKind: UpstreamInvalidFeed},
+		{name: "Empty", path: "/empty.xml", expectedKind: UpstreamInvalidFeed},
+		{name: "NoContent", path: "/no-content.xml", expectedKind: UpstreamUnexpectedStatus},
+		{name: "PartialContent", path: "/partial.xml", expectedKind: UpstreamUnexpectedStatus},
+	}
+
+	fetcher := NewRealFetcherService(http.DefaultClient)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := fetcher.fetchNews(context.Background(), ts.URL+tc.path)
+			if tc.expectedKind == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var upstreamErr *UpstreamError
+			if assert.True(t, errors.As(err, &upstreamErr)) {
+				assert.Equal(t, tc.expectedKind, upstreamErr.Kind)
+				assert.Equal(t, ts.URL+tc.path, upstreamErr.URL)
+				// the HTTP date has a resolution of seconds
+				assert.InDelta(t, tc.retryAfter, upstreamErr.RetryAfter, float64(time.Second))
+			}
+
+			var retriable *RetriableError
+			assert.Equal(t, tc.retryAfter > 0, errors.As(err, &retriable))
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 7, 25, 12, 0, 0, 0, time.UTC)
+
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		value      string
+		expected   time.Duration
+		expectedOK bool
+	}{
+		{value: "120", expected: 2 * time.Minute, expectedOK: true},
+		{value: "0", expected: 0, expectedOK: true},
+		{value: "Tue, 25 Jul 2023 12:01:30 GMT", expected: 90 * time.Second, expectedOK: true},
+		// a date in the past allows to retry right away
+		{value: "Tue, 25 Jul 2023 11:00:00 GMT", expected: 0, expectedOK: true},
+		{value: "-5"},
+		{value: "tomorrow"},
+		{value: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			retryAfter, ok := parseRetryAfter(tc.value, now)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expected, retryAfter)
+		})
+	}
+}
@@ -5,6 +5,10 @@ import (
 )
 
 var FxProvide = fx.Provide(
+	NewGuard,
+	NewOutboundHTTPClient,
 	NewRealFetcherService,
+	NewCircuitBreakers,
+	NewRetryPolicy,
 	NewService,
 )
@@ -8,33 +8,50 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 func (s Service) GetArticle(ctx context.Context, articleURL string) (model.Article, error) {
 	// Validate the URL
-	_, err := url.Parse(articleURL)
+	u, err := url.Parse(articleURL)
 	if err != nil {
 		return model.Article{}, ErrArgument{Err: fmt.Errorf("invalid URL: %v", err)}
 	}
 
-	resp, err := http.Get(articleURL)
+	if s.guard != nil {
+		err = s.guard.CheckURL(ctx, u)
+		if err != nil {
+			return model.Article{}, refusedURLError(err)
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, articleURL, nil)
 	if err != nil {
 		return model.Article{}, err
 	}
+
+	resp, err := s.httpClient.Do(request)
+	if err != nil {
+		return model.Article{}, refusedURLError(err)
+	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return model.Article{}, fmt.Errorf("failed to fetch article status code %d", resp.StatusCode)
+	if !isReadableStatus(resp.StatusCode) {
+		return model.Article{}, newUpstreamStatusError(articleURL, resp, time.Now())
 	}
 
 	// Parse the HTML content of the article
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return model.Article{}, err
+		return model.Article{}, &upstreamReadError{url: articleURL, err: err}
 	}
 
+	return parseArticle(doc, u), nil
+}
+
+func parseArticle(doc *goquery.Document, articleURL *url.URL) model.Article {
 	// Extract the title, description, and content of the article
-	var title, description, content string
+	var title, description, content, contentHTML string
 	doc.Find("h1").Each(func(i int, s *goquery.Selection) {
 		title = strings.TrimSpace(s.Text())
 	})
@@ -45,12 +62,37 @@ func (s Service) GetArticle(ctx context.Context, articleURL string) (model.Artic
 
 	doc.Find("article").Each(func(i int, s *goquery.Selection) {
 		content = strings.TrimSpace(s.Text())
+		contentHTML, _ = s.Html()
 	})
 
+	metadata := extractArticleMetadata(doc, articleURL)
+	wordCount := len(strings.Fields(content))
+
 	return model.Article{
-		Title:       title,
-		Description: description,
-		Link:        articleURL,
-		Content:     content,
-	}, nil
+		Title:        firstNonEmpty(metadata.Title, title),
+		Description:  firstNonEmpty(description, metadata.Description),
+		Link:         articleURL.String(),
+		Content:      content,
+		ContentHTML:  strings.TrimSpace(contentHTML),
+		CanonicalURL: metadata.CanonicalURL,
+		ImageURL:     metadata.ImageURL,
+		SiteName:     metadata.SiteName,
+		PublishedAt:  metadata.PublishedAt,
+		Author:       metadata.Author,
+		Section:      metadata.Section,
+		Keywords:     metadata.Keywords,
+
+		WordCount:          wordCount,
+		ReadingTimeMinutes: readingTimeMinutes(wordCount),
+	}
+}
+
+// wordsPerMinute is the average adult silent reading speed used to estimate reading time.
+const wordsPerMinute = 200
+
+func readingTimeMinutes(wordCount int) int {
+	if wordCount == 0 {
+		return 0
+	}
+	return (wordCount + wordsPerMinute - 1) / wordsPerMinute
 }
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/fir1/news/internal/news/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseArticle(t *testing.T) {
+	articleURL, err := url.Parse("https://www.example.com/news/article-1?at_medium=RSS")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name     string
+		html     string
+		expected model.Article
+	}{
+		{
+			name: "OpenGraph",
+			html: `<html><head>
+				<meta name="description" content="Plain description">
+				<meta property="og:title" content="OG Title">
+				<meta property="og:image" content="/images/hero.jpg">
+				<meta property="og:site_name" content="Example News">
+				<meta property="og:url" content="https://www.example.com/news/article-1">
+				<meta property="article:published_time" content="2023-07-25T07:00:00Z">
+				<meta property="article:section" content="Technology">
+				<meta property="article:tag" content="AI">
+				<meta property="article:tag" content="Robots">
+				<meta name="keywords" content="ai, science">
+				<meta name="author" content="Jane Doe">
+				</head><body><h1>Heading</h1><article>Body</article></body></html>`,
+			expected: model.Article{
+				Title:        "OG Title",
+				Description:  "Plain description",
+				CanonicalURL: "https://www.example.com/news/article-1",
+				ImageURL:     "https://www.example.com/images/hero.jpg",
+				SiteName:     "Example News",
+				PublishedAt:  time.Date(2023, 7, 25, 7, 0, 0, 0, time.UTC),
+				Author:       "Jane Doe",
+				Section:      "Technology",
+				Keywords:     []string{"AI", "Robots", "science"},
+			},
+		},
+		{
+			name: "JSONLD",
+			html: `<html><head>
+				<link rel="canonical" href="/news/article-1">
+				<meta name="twitter:image" content="https://cdn.example.com/twitter.jpg">
+				<script type="application/ld+json">{"@context":"https://schema.org","@graph":[
+					{"@type":"WebSite","name":"Ignored"},
+					{"@type":"NewsArticle","headline":"LD Headline","description":"LD description",
+					 "datePublished":"2023-07-25T10:00:00Z",
+					 "author":[{"@type":"Person","name":"Jane Doe"},{"@type":"Person","name":"John Roe"}],
+					 "publisher":{"@type":"Organization","name":"LD News","url":"https://www.example.com"},
+					 "articleSection":["Politics"],"keywords":["Election","Vote"]}
+				]}</script>
+				</head><body><h1>Heading</h1><article>Body</article></body></html>`,
+			expected: model.Article{
+				Title:        "LD Headline",
+				Description:  "LD description",
+				CanonicalURL: "https://www.example.com/news/article-1",
+				ImageURL:     "https://cdn.example.com/twitter.jpg",
+				SiteName:     "LD News",
+				PublishedAt:  time.Date(2023, 7, 25, 10, 0, 0, 0, time.UTC),
+				Author:       "Jane Doe, John Roe",
+				Section:      "Politics",
+				Keywords:     []string{"Election", "Vote"},
+			},
+		},
+		{
+			name: "NoMetadata",
+			html: `<html><head><title>Page</title></head><body><h1>Heading</h1><article>Body</article></body></html>`,
+			expected: model.Article{
+				Title: "Heading",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expected.Content = "Body"
+			tc.expected.ContentHTML = "Body"
+			tc.expected.Link = articleURL.String()
+			tc.expected.WordCount = 1
+			tc.expected.ReadingTimeMinutes = 1
+
+			assert.Equal(t, tc.expected, parseArticle(doc, articleURL))
+		})
+	}
+}
+
+func TestReadingTimeMinutes(t *testing.T) {
+	assert.Equal(t, 0, readingTimeMinutes(0))
+	assert.Equal(t, 1, readingTimeMinutes(1))
+	assert.Equal(t, 1, readingTimeMinutes(200))
+	assert.Equal(t, 2, readingTimeMinutes(201))
+}
+
+func TestGetArticleUpstreamStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			w.Write([]byte(`<html><body><h1>Title</h1><p>Content</p></body></html>`))
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/partial":
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte(`<html><body><h1>Title</h1>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name         string
+		path         string
+		expectedKind UpstreamErrorKind
+	}{
+		{name: "Success", path: "/article"},
+		{name: "NoContent", path: "/no-content", expectedKind: UpstreamUnexpectedStatus},
+		{name: "PartialContent", path: "/partial", expectedKind: UpstreamUnexpectedStatus},
+		{name: "NotFound", path: "/missing", expectedKind: UpstreamNotFound},
+	}
+
+	service := Service{httpClient: http.DefaultClient}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			article, err := service.GetArticle(context.Background(), ts.URL+tc.path)
+			if tc.expectedKind == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, "Title", article.Title)
+				return
+			}
+
+			var upstreamErr *UpstreamError
+			if assert.True(t, errors.As(err, &upstreamErr)) {
+				assert.Equal(t, tc.expectedKind, upstreamErr.Kind)
+			}
+		})
+	}
+}
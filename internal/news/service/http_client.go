@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/pkg/httpclient"
+	"github.com/fir1/news/pkg/netguard"
+	"net/http"
+)
+
+func NewGuard(cnf config.Config) (*netguard.Guard, error) {
+	return netguard.New(cnf.OutboundAllowedHosts, cnf.OutboundDeniedHosts)
+}
+
+// NewOutboundHTTPClient returns the client shared by every feed and article fetch,
+// every connection it opens, including redirects, is checked by the guard.
+func NewOutboundHTTPClient(cnf config.Config, guard *netguard.Guard) *http.Client {
+	return httpclient.New(httpclient.Config{
+		Timeout:               cnf.OutboundTimeout,
+		DialTimeout:           cnf.OutboundDialTimeout,
+		TLSHandshakeTimeout:   cnf.OutboundTLSHandshakeTimeout,
+		ResponseHeaderTimeout: cnf.OutboundResponseHeaderTimeout,
+		IdleConnTimeout:       cnf.OutboundIdleConnTimeout,
+		MaxIdleConns:          cnf.OutboundMaxIdleConns,
+		MaxIdleConnsPerHost:   cnf.OutboundMaxIdleConnsPerHost,
+		MaxConnsPerHost:       cnf.OutboundMaxConnsPerHost,
+		MaxResponseBytes:      cnf.OutboundMaxResponseBytes,
+		UserAgent:             cnf.OutboundUserAgent,
+
+		RequestsPerSecondPerHost: cnf.OutboundRateLimitPerHost,
+		BurstPerHost:             cnf.OutboundRateLimitBurst,
+		MaxConcurrentPerHost:     cnf.OutboundMaxConcurrentPerHost,
+	}, guard)
+}
+
+// refusedURLError reports URLs refused by the guard as an invalid argument of the client.
+func refusedURLError(err error) error {
+	if errors.Is(err, netguard.ErrForbiddenDestination) {
+		return ErrArgument{Err: err}
+	}
+	return err
+}
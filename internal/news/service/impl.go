@@ -1,17 +1,37 @@
 package service
 
-import "github.com/fir1/news/pkg/cache"
+import (
+	"github.com/fir1/news/pkg/cache"
+	"github.com/fir1/news/pkg/circuitbreaker"
+	"github.com/fir1/news/pkg/coalesce"
+	"github.com/fir1/news/pkg/netguard"
+	"net/http"
+)
 
 type Service struct {
 	NewsFetcher NewsFetcher
 	cacheClient cache.CacheClientInterface
+	httpClient  *http.Client
+	guard       *netguard.Guard
+	breakers    *circuitbreaker.Registry
+	retryPolicy RetryPolicy
+	feedFetches *coalesce.Group[RSS]
 }
 
 func NewService(nf NewsFetcher,
 	cc cache.CacheClientInterface,
+	httpClient *http.Client,
+	guard *netguard.Guard,
+	breakers *circuitbreaker.Registry,
+	retryPolicy RetryPolicy,
 ) NewsInterface {
 	return Service{
 		NewsFetcher: nf,
 		cacheClient: cc,
+		httpClient:  httpClient,
+		guard:       guard,
+		breakers:    breakers,
+		retryPolicy: retryPolicy,
+		feedFetches: &coalesce.Group[RSS]{},
 	}
 }
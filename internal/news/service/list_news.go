@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/avast/retry-go"
 	"github.com/fir1/news/internal/news/model"
+	"github.com/fir1/news/pkg/circuitbreaker"
+	"github.com/fir1/news/pkg/netguard"
+	"github.com/fir1/news/pkg/urlnorm"
 	"net/url"
 	"sort"
 	"strings"
@@ -22,6 +24,31 @@ type ListNewsParams struct {
 
 type ListNewsResponse struct {
 	NewsFeeds []model.NewsFeed
+	// Sources reports the outcome of every feed the news were collected from
+	Sources []SourceStatus
+}
+
+type SourceState string
+
+const (
+	SourceStateOK     SourceState = "ok"
+	SourceStateFailed SourceState = "failed"
+	// SourceStateSkipped is reported for sources whose circuit breaker is open
+	SourceStateSkipped SourceState = "skipped"
+)
+
+type SourceStatus struct {
+	URL      string
+	Provider model.NewsProvider
+	Category string
+	State    SourceState
+	Err      error
+}
+
+type newsSource struct {
+	url      string
+	provider model.NewsProvider
+	category string
 }
 
 func (s Service) ListNews(ctx context.Context, params ListNewsParams) (ListNewsResponse, error) {
@@ -37,15 +64,12 @@ func (s Service) ListNews(ctx context.Context, params ListNewsParams) (ListNewsR
 		return ListNewsResponse{}, ErrArgument{Err: errors.New("please provide one of value for providers or news_source_url can not proceed both")}
 	}
 
-	newsFeedCh := make(chan []model.NewsFeed)
-	errorCh := make(chan error)
-	var wg sync.WaitGroup
-
 	// in case both providers and new_source_url not provided by client, we will take all available news_providers by default
 	if params.Providers == nil && params.NewsSourceURL == nil {
 		params.Providers = &[]model.NewsProvider{model.NewsProviderBBC, model.NewsProviderSky}
 	}
 
+	var sources []newsSource
 	if params.Providers != nil {
 		for _, provider := range *params.Providers {
 			if !provider.Valid() {
@@ -56,64 +80,52 @@ func (s Service) ListNews(ctx context.Context, params ListNewsParams) (ListNewsR
 				if category != "general" && category != "technology" {
 					return ListNewsResponse{}, ErrArgument{Err: fmt.Errorf("category: %s is invalid must be `general`, `technology`", category)}
 				}
-				wg.Add(1)
 
 				feedURL, found := feedURLs[provider][category]
 				if !found {
 					feedURL = feedURLs[provider]["general"]
 				}
-
-				go func(wg *sync.WaitGroup, feedURL string, provider model.NewsProvider) {
-					defer wg.Done()
-					newsFeed, err := s.getProviderNewsFeed(ctx, feedURL, provider)
-					if err != nil {
-						errorCh <- err
-						return
-					}
-					newsFeedCh <- newsFeed
-				}(&wg, feedURL, provider)
+				sources = append(sources, newsSource{url: feedURL, provider: provider, category: category})
 			}
 		}
 	}
 
 	if params.NewsSourceURL != nil {
-		wg.Add(1)
+		sources = append(sources, newsSource{url: *params.NewsSourceURL, provider: model.NewsProviderOther})
+	}
 
-		go func(wg *sync.WaitGroup) {
+	newsFeeds := make([][]model.NewsFeed, len(sources))
+	statuses := make([]SourceStatus, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source newsSource) {
 			defer wg.Done()
-			newsFeed, err := s.getProviderNewsFeed(ctx, *params.NewsSourceURL, model.NewsProviderOther)
-			if err != nil {
-				errorCh <- err
-				return
-			}
-			newsFeedCh <- newsFeed
-		}(&wg)
+			newsFeeds[i], statuses[i] = s.getSourceNewsFeed(ctx, source)
+		}(i, source)
 	}
+	wg.Wait()
 
-	// Wait for all goroutines to finish and close the result channel
-	go func() {
-		wg.Wait()
-		close(newsFeedCh)
-	}()
-
+	// a single failing or skipped source does not fail the whole list, its status is
+	// reported instead, only when no source succeeded the first error is returned
 	var combinedResult ListNewsResponse
-	run := true
-	for run {
-		select {
-		case result, ok := <-newsFeedCh:
-			if ok {
-				combinedResult.NewsFeeds = append(combinedResult.NewsFeeds, result...)
-			} else {
-				// resultCh is closed, we are done processing results
-				run = false
+	var firstErr error
+	for i, status := range statuses {
+		if status.Err != nil {
+			var errArgument ErrArgument
+			if errors.As(status.Err, &errArgument) {
+				return ListNewsResponse{}, status.Err
 			}
-		case err, ok := <-errorCh:
-			if ok {
-				return ListNewsResponse{}, err
+			if firstErr == nil {
+				firstErr = status.Err
 			}
-			// errorCh is closed, we are done processing errors
-			run = false
 		}
+		combinedResult.NewsFeeds = append(combinedResult.NewsFeeds, newsFeeds[i]...)
+		combinedResult.Sources = append(combinedResult.Sources, status)
+	}
+
+	if !combinedResult.anySourceOK() {
+		return ListNewsResponse{}, firstErr
 	}
 
 	if params.SortByPublishDate == SortASC {
@@ -125,6 +137,39 @@ func (s Service) ListNews(ctx context.Context, params ListNewsParams) (ListNewsR
 	return combinedResult, nil
 }
 
+func (r ListNewsResponse) anySourceOK() bool {
+	for _, status := range r.Sources {
+		if status.State == SourceStateOK {
+			return true
+		}
+	}
+	return false
+}
+
+// getSourceNewsFeed fetches a single source, sources with an open circuit breaker are
+// skipped without calling the upstream.
+func (s Service) getSourceNewsFeed(ctx context.Context, source newsSource) ([]model.NewsFeed, SourceStatus) {
+	status := SourceStatus{
+		URL:      source.url,
+		Provider: source.provider,
+		Category: source.category,
+		State:    SourceStateOK,
+	}
+
+	newsFeed, err := s.getProviderNewsFeed(ctx, source.url, source.provider)
+	switch {
+	case errors.Is(err, circuitbreaker.ErrOpen):
+		status.State = SourceStateSkipped
+		status.Err = fmt.Errorf("%s: %w", source.url, err)
+		return nil, status
+	case err != nil:
+		status.State = SourceStateFailed
+		status.Err = err
+		return nil, status
+	}
+	return newsFeed, status
+}
+
 var feedURLs = map[model.NewsProvider]map[string]string{
 	model.NewsProviderBBC: {
 		"general":    "http://feeds.bbci.co.uk/news/uk/rss.xml",
@@ -141,31 +186,23 @@ func (s Service) getProviderNewsFeed(ctx context.Context, feedURL string, provid
 		return nil, ErrArgument{Err: fmt.Errorf("url: %s not valid, please provide a valid url ending with `.xml`", feedURL)}
 	}
 
-	var response []model.NewsFeed
-	var feeds RSS
-	err := retry.Do(
-		func() error {
-			var err error
-			feeds, err = s.NewsFetcher.fetchNews(ctx, feedURL)
-			return err
-		},
+	if s.guard != nil {
+		u, _ := url.Parse(feedURL)
+		if err := s.guard.CheckURL(ctx, u); err != nil {
+			return nil, refusedURLError(err)
+		}
+	}
 
-		retry.DelayType(func(n uint, err error, config *retry.Config) time.Duration {
-			if retriable, ok := err.(*RetriableError); ok {
-				return retriable.RetryAfter
-			}
-			// apply a default exponential back off strategy
-			return retry.BackOffDelay(n, err, config)
-		}),
-	)
+	var response []model.NewsFeed
+	feeds, err := s.fetchFeed(ctx, feedURL)
 	if err != nil {
-		return nil, err
+		return nil, refusedURLError(err)
 	}
 
 	for _, item := range feeds.Channel.Items {
 		pubDate, err := parseTimeFromString(item.PubDate)
 		if err != nil {
-			return nil, err
+			return nil, &UpstreamError{Kind: UpstreamInvalidFeed, URL: feedURL, Err: err}
 		}
 
 		newsFeed := model.NewsFeed{
@@ -182,6 +219,48 @@ func (s Service) getProviderNewsFeed(ctx context.Context, feedURL string, provid
 	return response, nil
 }
 
+// fetchFeed fetches a feed with retries through its circuit breaker, concurrent fetches
+// of the same feed, e.g. by different news lists containing it, share a single fetch and
+// its outcome is recorded by the breaker once.
+func (s Service) fetchFeed(ctx context.Context, feedURL string) (RSS, error) {
+	// spellings of a feed which share the fetch share its circuit breaker as well
+	key := urlnorm.Canonical(feedURL)
+	fetch := func(ctx context.Context) (RSS, error) {
+		var breaker *circuitbreaker.Breaker
+		if s.breakers != nil {
+			breaker = s.breakers.Get(key)
+			err := breaker.Allow()
+			if err != nil {
+				return RSS{}, err
+			}
+		}
+
+		var feeds RSS
+		err := s.retryPolicy.do(ctx, func(ctx context.Context) error {
+			var err error
+			feeds, err = s.NewsFetcher.fetchNews(ctx, feedURL)
+			return err
+		})
+		if breaker != nil {
+			switch {
+			case err == nil:
+				breaker.Success()
+			case errors.Is(err, netguard.ErrForbiddenDestination) || ctx.Err() != nil:
+				// neither a refused redirect nor a client which went away says anything about the upstream
+				breaker.Cancel()
+			default:
+				breaker.Failure(err)
+			}
+		}
+		return feeds, err
+	}
+
+	if s.feedFetches == nil {
+		return fetch(ctx)
+	}
+	return s.feedFetches.Do(ctx, key, fetch)
+}
+
 func isValidURL(input string) bool {
 	// Parse the input string as a URL
 	u, err := url.Parse(input)
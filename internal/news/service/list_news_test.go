@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"github.com/fir1/news/internal/news/model"
+	"github.com/fir1/news/pkg/circuitbreaker"
+	"github.com/fir1/news/pkg/coalesce"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"sync"
 	"testing"
+	"time"
 )
 
 // MockService is a mock implementation of the Service struct that satisfies the NewsFetcher interface
@@ -137,3 +141,144 @@ func TestListNews(t *testing.T) {
 func StrPointer(str string) *string {
 	return &str
 }
+
+func TestListNewsSkipsOpenCircuit(t *testing.T) {
+	ctx := context.Background()
+	providers := []model.NewsProvider{model.NewsProviderBBC, model.NewsProviderSky}
+
+	mockService := new(MockService)
+	mockService.On("fetchNews", ctx, "http://feeds.bbci.co.uk/news/uk/rss.xml").Return(RSS{
+		Channel: Channel{
+			Items: []Item{
+				{
+					Title:   CDATA{Text: "Item 1 Title"},
+					PubDate: "Mon, 04 Jan 2023 15:04:05 GMT",
+				},
+			},
+		},
+	}, nil)
+
+	breakers := circuitbreaker.NewRegistry(circuitbreaker.Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+	breakers.Get("http://feeds.skynews.com/feeds/rss/uk.xml").Failure(errors.New("server error"))
+
+	service := Service{
+		NewsFetcher: mockService,
+		breakers:    breakers,
+	}
+
+	response, err := service.ListNews(ctx, ListNewsParams{Providers: &providers})
+	assert.NoError(t, err)
+	assert.Len(t, response.NewsFeeds, 1)
+	assert.Len(t, response.Sources, 2)
+
+	assert.Equal(t, SourceStateOK, response.Sources[0].State)
+	assert.Equal(t, model.NewsProvider(model.NewsProviderBBC), response.Sources[0].Provider)
+
+	// the open source is skipped without calling the upstream
+	assert.Equal(t, SourceStateSkipped, response.Sources[1].State)
+	assert.Equal(t, "general", response.Sources[1].Category)
+	assert.ErrorIs(t, response.Sources[1].Err, circuitbreaker.ErrOpen)
+	mockService.AssertExpectations(t)
+	mockService.AssertNumberOfCalls(t, "fetchNews", 1)
+
+	// when every source is skipped the list fails
+	providers = providers[1:]
+	_, err = service.ListNews(ctx, ListNewsParams{Providers: &providers})
+	assert.ErrorIs(t, err, circuitbreaker.ErrOpen)
+}
+
+func TestListNewsCoalescesFeedFetches(t *testing.T) {
+	providers := []model.NewsProvider{model.NewsProviderBBC}
+
+	mockService := new(MockService)
+	mockService.On("fetchNews", mock.Anything, "http://feeds.bbci.co.uk/news/uk/rss.xml").
+		After(50*time.Millisecond).
+		Return(RSS{
+			Channel: Channel{
+				Items: []Item{
+					{
+						Title:   CDATA{Text: "Item 1 Title"},
+						PubDate: "Mon, 04 Jan 2023 15:04:05 GMT",
+					},
+				},
+			},
+		}, nil)
+
+	service := Service{
+		NewsFetcher: mockService,
+		feedFetches: &coalesce.Group[RSS]{},
+	}
+
+	// concurrent lists containing the same feed share a single fetch
+	const requests = 5
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := service.ListNews(context.Background(), ListNewsParams{Providers: &providers})
+			assert.NoError(t, err)
+			assert.Len(t, response.NewsFeeds, 1)
+		}()
+	}
+	wg.Wait()
+
+	mockService.AssertNumberOfCalls(t, "fetchNews", 1)
+}
+
+func TestListNewsRecordsCoalescedFailureOnce(t *testing.T) {
+	providers := []model.NewsProvider{model.NewsProviderBBC}
+	feedURL := "http://feeds.bbci.co.uk/news/uk/rss.xml"
+
+	mockService := new(MockService)
+	mockService.On("fetchNews", mock.Anything, feedURL).
+		After(50*time.Millisecond).
+		Return(RSS{}, errors.New("server error"))
+
+	breakers := circuitbreaker.NewRegistry(circuitbreaker.Config{FailureThreshold: 3, OpenTimeout: time.Minute})
+	service := Service{
+		NewsFetcher: mockService,
+		breakers:    breakers,
+		retryPolicy: RetryPolicy{Attempts: 1},
+		feedFetches: &coalesce.Group[RSS]{},
+	}
+
+	// every waiter sees the failure of the shared fetch, the breaker counts it once
+	const requests = 5
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.ListNews(context.Background(), ListNewsParams{Providers: &providers})
+			assert.EqualError(t, err, "server error")
+		}()
+	}
+	wg.Wait()
+
+	mockService.AssertNumberOfCalls(t, "fetchNews", 1)
+	snapshot := breakers.Get(feedURL).Snapshot()
+	assert.Equal(t, 1, snapshot.ConsecutiveFailures)
+	assert.Equal(t, circuitbreaker.StateClosed, snapshot.State)
+}
+
+func TestListNewsSharesCircuitBreakerOfFeedSpellings(t *testing.T) {
+	ctx := context.Background()
+	mockService := new(MockService)
+
+	breakers := circuitbreaker.NewRegistry(circuitbreaker.Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+	breakers.Get("http://example.com/feed.xml?foo=").Failure(errors.New("server error"))
+
+	service := Service{
+		NewsFetcher: mockService,
+		breakers:    breakers,
+	}
+
+	// `?foo` and `?foo=` share the cache entry and the fetch, so they share the breaker
+	for _, feedURL := range []string{"http://example.com/feed.xml?foo", "HTTP://EXAMPLE.COM:80/feed.xml?foo="} {
+		_, err := service.ListNews(ctx, ListNewsParams{NewsSourceURL: StrPointer(feedURL)})
+		assert.ErrorIs(t, err, circuitbreaker.ErrOpen)
+	}
+	mockService.AssertNotCalled(t, "fetchNews", mock.Anything, mock.Anything)
+	assert.Len(t, breakers.Snapshot(), 1)
+}
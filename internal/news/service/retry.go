@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/avast/retry-go"
+	"github.com/fir1/news/config"
+	"github.com/fir1/news/pkg/netguard"
+	"github.com/fir1/news/pkg/ratelimit"
+	"time"
+)
+
+// RetryPolicy controls how often and how long a failing feed is fetched again.
+type RetryPolicy struct {
+	// Attempts is the total number of fetches including the first one
+	Attempts uint
+	// BaseDelay is the first backoff delay, it doubles with every attempt up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxRetryAfter is the longest Retry-After we wait for, a server asking for more
+	// is not retried at all
+	MaxRetryAfter time.Duration
+	// Budget caps the total time spent on all attempts, zero means only the deadline
+	// of the request context applies
+	Budget time.Duration
+}
+
+// defaultRetryPolicy is used when no policy was configured, it matches the retry-go defaults.
+var defaultRetryPolicy = RetryPolicy{
+	Attempts:      10,
+	BaseDelay:     100 * time.Millisecond,
+	MaxDelay:      10 * time.Second,
+	MaxRetryAfter: time.Minute,
+}
+
+func NewRetryPolicy(cnf config.Config) RetryPolicy {
+	return RetryPolicy{
+		Attempts:      cnf.FeedRetryAttempts,
+		BaseDelay:     cnf.FeedRetryBaseDelay,
+		MaxDelay:      cnf.FeedRetryMaxDelay,
+		MaxRetryAfter: cnf.FeedRetryMaxRetryAfter,
+		Budget:        cnf.FeedRetryBudget,
+	}
+}
+
+// do calls fn until it succeeds or the policy gives up and returns the last error,
+// a retry is only attempted when its delay ends before the deadline of ctx.
+func (p RetryPolicy) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.Attempts == 0 {
+		p = defaultRetryPolicy
+	}
+
+	if p.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Budget)
+		defer cancel()
+	}
+
+	var attempt uint
+	return retry.Do(
+		func() error {
+			attempt++
+			return fn(ctx)
+		},
+		retry.Attempts(p.Attempts),
+		retry.DelayType(func(n uint, err error, config *retry.Config) time.Duration {
+			return p.delay(n, err)
+		}),
+		retry.RetryIf(func(err error) bool {
+			if !p.retriable(err) || ctx.Err() != nil {
+				return false
+			}
+			// do not sleep when the next attempt could not finish before the deadline
+			deadline, ok := ctx.Deadline()
+			return !ok || time.Now().Add(p.delay(attempt-1, err)).Before(deadline)
+		}),
+		retry.LastErrorOnly(true),
+		// stop waiting for the next attempt once the client went away
+		retry.Context(ctx),
+	)
+}
+
+// delay returns how long to wait after the n-th (zero based) failed attempt.
+func (p RetryPolicy) delay(n uint, err error) time.Duration {
+	var retriable *RetriableError
+	if errors.As(err, &retriable) {
+		return retriable.RetryAfter
+	}
+
+	// apply an exponential back off strategy
+	const maxShift = 30
+	if n > maxShift {
+		n = maxShift
+	}
+	delay := p.BaseDelay << n
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+func (p RetryPolicy) retriable(err error) bool {
+	if !retry.IsRecoverable(err) {
+		return false
+	}
+
+	// a redirect to a forbidden destination will be refused again and the
+	// turn at the rate limiter would not come before the deadline either
+	if errors.Is(err, netguard.ErrForbiddenDestination) || errors.Is(err, ratelimit.ErrDeadlineExceeded) {
+		return false
+	}
+
+	var retriable *RetriableError
+	if errors.As(err, &retriable) && retriable.RetryAfter > p.MaxRetryAfter {
+		return false
+	}
+
+	// a missing page or a broken feed will not change by asking again
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) && !upstreamErr.Retriable() {
+		return false
+	}
+	return true
+}
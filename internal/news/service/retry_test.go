@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/avast/retry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	const feedURL = "http://feeds.bbci.co.uk/news/uk/rss.xml"
+	policy := RetryPolicy{
+		Attempts:      3,
+		BaseDelay:     time.Millisecond,
+		MaxDelay:      5 * time.Millisecond,
+		MaxRetryAfter: 10 * time.Millisecond,
+	}
+
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name          string
+		err           error
+		timeout       time.Duration
+		expectedCalls int
+	}{
+		{
+			name:          "ServerErrorIsRetried",
+			err:           &UpstreamError{Kind: UpstreamServerError, URL: feedURL, StatusCode: http.StatusInternalServerError},
+			expectedCalls: 3,
+		},
+		{
+			name:          "ClientErrorIsNotRetried",
+			err:           &UpstreamError{Kind: UpstreamNotFound, URL: feedURL, StatusCode: http.StatusNotFound},
+			expectedCalls: 1,
+		},
+		{
+			name: "ShortRetryAfterIsHonoured",
+			err: &RetriableError{
+				Err:        &UpstreamError{Kind: UpstreamRateLimited, URL: feedURL, StatusCode: http.StatusTooManyRequests},
+				RetryAfter: 5 * time.Millisecond,
+			},
+			expectedCalls: 3,
+		},
+		{
+			name: "LongRetryAfterIsNotWaitedFor",
+			err: &RetriableError{
+				Err:        &UpstreamError{Kind: UpstreamRateLimited, URL: feedURL, StatusCode: http.StatusTooManyRequests},
+				RetryAfter: time.Hour,
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:          "InvalidFeedIsNotRetried",
+			err:           &UpstreamError{Kind: UpstreamInvalidFeed, URL: feedURL, StatusCode: http.StatusOK, Err: errors.New("EOF")},
+			expectedCalls: 1,
+		},
+		{
+			name:          "UnrecoverableIsNotRetried",
+			err:           retry.Unrecoverable(&UpstreamError{Kind: UpstreamRateLimited, URL: feedURL, StatusCode: http.StatusTooManyRequests}),
+			expectedCalls: 1,
+		},
+		{
+			// the back off of 1ms and 2ms fits into the deadline
+			name:          "RetriesWithinDeadline",
+			err:           &UpstreamError{Kind: UpstreamServerError, URL: feedURL, StatusCode: http.StatusBadGateway},
+			timeout:       50 * time.Millisecond,
+			expectedCalls: 3,
+		},
+		{
+			name: "RetryAfterPastDeadline",
+			err: &RetriableError{
+				Err:        &UpstreamError{Kind: UpstreamRateLimited, URL: feedURL, StatusCode: http.StatusTooManyRequests},
+				RetryAfter: 8 * time.Millisecond,
+			},
+			timeout:       5 * time.Millisecond,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			mockService := new(MockService)
+			mockService.On("fetchNews", mock.Anything, feedURL).Return(RSS{}, tc.err)
+
+			service := Service{
+				NewsFetcher: mockService,
+				retryPolicy: policy,
+			}
+
+			_, err := service.getProviderNewsFeed(ctx, feedURL, "bbc")
+
+			var upstreamErr *UpstreamError
+			assert.True(t, errors.As(err, &upstreamErr))
+			mockService.AssertNumberOfCalls(t, "fetchNews", tc.expectedCalls)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNotSupported is returned for administrative operations a backend does not implement.
+var ErrNotSupported = errors.New("operation not supported by the cache backend")
+
+// Stats describes the usage of a cache, values a backend can not tell are zero.
+type Stats struct {
+	Backend   string
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int64
+	Bytes     int64
+	// Tiers holds the stats of every tier of a TwoTier cache
+	Tiers []Stats
+}
+
+// StatsReporter is implemented by caches which can report their usage.
+type StatsReporter interface {
+	Stats() (Stats, error)
+}
+
+// PrefixDeleter is implemented by caches which can delete every entry whose key starts
+// with a prefix, e.g. all cached articles of one site.
+type PrefixDeleter interface {
+	DeletePrefix(prefix string) (int, error)
+}
+
+// StatsOf returns the stats of c when its backend reports them.
+func StatsOf(c CacheClientInterface) (Stats, error) {
+	reporter, ok := c.(StatsReporter)
+	if !ok {
+		return Stats{}, ErrNotSupported
+	}
+	return reporter.Stats()
+}
+
+// DeletePrefix deletes every entry of c whose key starts with prefix and returns their number.
+func DeletePrefix(c CacheClientInterface, prefix string) (int, error) {
+	deleter, ok := c.(PrefixDeleter)
+	if !ok {
+		return 0, ErrNotSupported
+	}
+	return deleter.DeletePrefix(prefix)
+}
+
+// counters keeps the hits and misses of a backend which does not count them itself.
+type counters struct {
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+// record counts the outcome of a Get.
+func (c *counters) record(err error) {
+	switch {
+	case err == nil:
+		c.hits.Add(1)
+	case errors.Is(err, ErrEntryNotFound):
+		c.misses.Add(1)
+	}
+}
+
+func (c *counters) stats(backend string) Stats {
+	return Stats{
+		Backend:   backend,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
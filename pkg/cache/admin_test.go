@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAdministration(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	newBigcache := func(t *testing.T) CacheClientInterface {
+		cache, err := NewBigcache(time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cache
+	}
+
+	tests := []struct {
+		name    string
+		backend string
+		new     func(t *testing.T) CacheClientInterface
+		// bytes of the entries before and after the deletes, zero when not reported
+		bytes        int64
+		bytesDeleted int64
+	}{
+		{
+			name:         "Memory",
+			backend:      "memory",
+			new:          newBigcache,
+			bytes:        147,
+			bytesDeleted: 58,
+		},
+		{
+			name:    "Redis",
+			backend: "redis",
+			new: func(t *testing.T) CacheClientInterface {
+				server.FlushAll()
+				return NewRedis(client, "news:", time.Hour)
+			},
+		},
+		{
+			name:    "Disk",
+			backend: "disk",
+			new: func(t *testing.T) CacheClientInterface {
+				cache, err := NewDisk(filepath.Join(t.TempDir(), "news.db"), time.Hour)
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { cache.Close() })
+				return cache
+			},
+		},
+		{
+			name:    "TwoTier",
+			backend: "two-tier",
+			new: func(t *testing.T) CacheClientInterface {
+				server.FlushAll()
+				cache, err := NewTwoTier(context.Background(), newBigcache(t), NewRedis(client, "news:", time.Hour), time.Minute, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return cache
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := tt.new(t)
+
+			assert.NoError(t, cache.Set("news:category=uk", []byte("news")))
+			assert.NoError(t, cache.Set("article:https://www.bbc.co.uk/news/1?at=rss", []byte("bbc 1")))
+			assert.NoError(t, cache.Set("article:https://www.bbc.co.uk/news/2", []byte("bbc 2")))
+			assert.NoError(t, cache.Set("article:https://www.sky.com/story/1", []byte("sky")))
+
+			_, err := cache.Get("news:category=uk")
+			assert.NoError(t, err)
+			_, err = cache.Get("news:category=tech")
+			assert.ErrorIs(t, err, ErrEntryNotFound)
+
+			stats, err := StatsOf(cache)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.backend, stats.Backend)
+			assert.Equal(t, int64(1), stats.Hits)
+			assert.Equal(t, int64(1), stats.Misses)
+			assert.Equal(t, int64(4), stats.Entries)
+			if tt.bytes != 0 {
+				assert.Equal(t, tt.bytes, stats.Bytes)
+			}
+
+			// the prefix is matched literally, "?" is no pattern
+			deleted, err := DeletePrefix(cache, "article:https://www.bbc.co.uk/news/1?")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, deleted)
+
+			deleted, err = DeletePrefix(cache, "article:https://www.bbc.co.uk/")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, deleted)
+
+			_, err = cache.Get("article:https://www.bbc.co.uk/news/2")
+			assert.ErrorIs(t, err, ErrEntryNotFound)
+			_, err = cache.Get("article:https://www.sky.com/story/1")
+			assert.NoError(t, err)
+
+			stats, err = StatsOf(cache)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), stats.Entries)
+			if tt.bytesDeleted != 0 {
+				assert.Equal(t, tt.bytesDeleted, stats.Bytes)
+			}
+		})
+	}
+
+	t.Run("NotSupported", func(t *testing.T) {
+		cache := &TwoTier{l1: newBigcache(t), l2: unsupported{}}
+		_, err := StatsOf(cache)
+		assert.ErrorIs(t, err, ErrNotSupported)
+		_, err = DeletePrefix(cache, "news:")
+		assert.ErrorIs(t, err, ErrNotSupported)
+	})
+}
+
+// unsupported is a cache without any administrative operations.
+type unsupported struct {
+	CacheClientInterface
+}
@@ -2,37 +2,142 @@ package cache
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"github.com/allegro/bigcache/v3"
+	"strings"
 	"time"
 )
 
+// expiryHeaderSize is the size of the expiry time stored in front of every entry,
+// bigcache itself only knows a single lifetime for all entries.
+const expiryHeaderSize = 8
+
 type Bigcache struct {
-	client *bigcache.BigCache
+	client     *bigcache.BigCache
+	lifeWindow time.Duration
+	now        func() time.Time
+	// counters is shared by the copies of the value
+	counters *counters
 }
 
-func NewBigcache() (CacheClientInterface, error) {
-	clientCache, err := bigcache.New(context.Background(), bigcache.DefaultConfig(5*time.Minute))
+// NewBigcache returns an in memory cache whose entries live for lifeWindow, entries
+// stored with a longer TTL are still evicted after lifeWindow.
+func NewBigcache(lifeWindow time.Duration) (CacheClientInterface, error) {
+	stats := &counters{}
+	config := bigcache.DefaultConfig(lifeWindow)
+	config.OnRemoveWithReason = func(_ string, _ []byte, reason bigcache.RemoveReason) {
+		if reason != bigcache.Deleted {
+			stats.evictions.Add(1)
+		}
+	}
+
+	clientCache, err := bigcache.New(context.Background(), config)
 	if err != nil {
 		return nil, err
 	}
 
 	return Bigcache{
-		client: clientCache,
+		client:     clientCache,
+		lifeWindow: lifeWindow,
+		now:        time.Now,
+		counters:   stats,
 	}, nil
 }
 
 func (b Bigcache) Get(key string) ([]byte, error) {
-	return b.client.Get(key)
+	entry, err := b.get(key)
+	b.counters.record(err)
+	return entry, err
+}
+
+func (b Bigcache) get(key string) ([]byte, error) {
+	entry, err := b.client.Get(key)
+	if errors.Is(err, bigcache.ErrEntryNotFound) {
+		return nil, ErrEntryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entry) < expiryHeaderSize {
+		return nil, ErrEntryNotFound
+	}
+	expiresAt := int64(binary.BigEndian.Uint64(entry))
+	if b.now().UnixNano() >= expiresAt {
+		b.counters.evictions.Add(1)
+		_ = b.client.Delete(key)
+		return nil, ErrEntryNotFound
+	}
+	return entry[expiryHeaderSize:], nil
 }
 
 func (b Bigcache) Set(key string, entry []byte) error {
-	return b.client.Set(key, entry)
+	return b.SetWithTTL(key, entry, b.lifeWindow)
+}
+
+func (b Bigcache) SetWithTTL(key string, entry []byte, ttl time.Duration) error {
+	if ttl <= 0 || ttl > b.lifeWindow {
+		ttl = b.lifeWindow
+	}
+
+	data := make([]byte, expiryHeaderSize+len(entry))
+	binary.BigEndian.PutUint64(data, uint64(b.now().Add(ttl).UnixNano()))
+	copy(data[expiryHeaderSize:], entry)
+	return b.client.Set(key, data)
 }
 
 func (b Bigcache) Delete(key string) error {
-	return b.client.Delete(key)
+	err := b.client.Delete(key)
+	if errors.Is(err, bigcache.ErrEntryNotFound) {
+		return nil
+	}
+	return err
 }
 
 func (b Bigcache) Reset() error {
 	return b.client.Reset()
 }
+
+// Stats reports the entries and the bytes of their keys and values, evictions count the
+// entries dropped because they expired or the cache ran out of space. The hits, misses
+// and evictions are counted by Bigcache rather than taken from bigcache.Stats, which
+// would count an entry past its own TTL as a hit, and the bytes are summed up as
+// bigcache only reports the size of its allocated queues.
+func (b Bigcache) Stats() (Stats, error) {
+	stats := b.counters.stats("memory")
+	stats.Entries = int64(b.client.Len())
+
+	iter := b.client.Iterator()
+	for iter.SetNext() {
+		info, err := iter.Value()
+		if err != nil {
+			continue
+		}
+		stats.Bytes += int64(len(info.Key()) + len(info.Value()) - expiryHeaderSize)
+	}
+	return stats, nil
+}
+
+func (b Bigcache) DeletePrefix(prefix string) (int, error) {
+	// deleting while iterating is not safe, the keys are collected first
+	var keys []string
+	iter := b.client.Iterator()
+	for iter.SetNext() {
+		info, err := iter.Value()
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(info.Key(), prefix) {
+			keys = append(keys, info.Key())
+		}
+	}
+
+	for _, key := range keys {
+		err := b.Delete(key)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
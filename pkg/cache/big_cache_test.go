@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBigcacheTTL(t *testing.T) {
+	client, err := NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	cache := client.(Bigcache)
+	cache.now = func() time.Time { return now }
+
+	assert.NoError(t, cache.Set("default", []byte("1")))
+	assert.NoError(t, cache.SetWithTTL("short", []byte("2"), time.Minute))
+	// a TTL longer than the life window is capped
+	assert.NoError(t, cache.SetWithTTL("long", []byte("3"), 2*time.Hour))
+
+	entry, err := cache.Get("short")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", string(entry))
+
+	now = now.Add(2 * time.Minute)
+	_, err = cache.Get("short")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+	entry, err = cache.Get("default")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", string(entry))
+
+	now = now.Add(time.Hour)
+	_, err = cache.Get("default")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+	_, err = cache.Get("long")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+
+	_, err = cache.Get("missing")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+	assert.NoError(t, cache.Delete("missing"))
+}
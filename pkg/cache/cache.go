@@ -1,9 +1,38 @@
 package cache
 
-// CacheClientInterface represents a allegro/bigcache client
+import (
+	"errors"
+	"time"
+)
+
+// ErrEntryNotFound is returned by Get when the key is not cached or has expired
+var ErrEntryNotFound = errors.New("entry not found")
+
+// CacheClientInterface represents a cache client, it is implemented in memory by
+// allegro/bigcache and shared between replicas by redis
 type CacheClientInterface interface {
 	Get(key string) ([]byte, error)
+	// Set stores an entry for the default lifetime of the cache
 	Set(key string, entry []byte) error
+	// SetWithTTL stores an entry which expires after ttl
+	SetWithTTL(key string, entry []byte, ttl time.Duration) error
 	Delete(key string) error
 	Reset() error
 }
+
+// GetOrLoad returns the cached entry of key, on a miss the entry is loaded and stored for ttl.
+// The cache only speeds up loading, an entry which can not be read or stored is loaded
+// and returned anyway instead of failing the caller.
+func GetOrLoad(c CacheClientInterface, key string, ttl time.Duration, load func() ([]byte, error)) ([]byte, error) {
+	entry, err := c.Get(key)
+	if err == nil {
+		return entry, nil
+	}
+
+	entry, err = load()
+	if err != nil {
+		return nil, err
+	}
+	_ = c.SetWithTTL(key, entry, ttl)
+	return entry, nil
+}
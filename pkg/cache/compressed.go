@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// EncodingGzip is the encoding of entries compressed by Compressed, named like the
+// HTTP content coding so compressed entries can be sent to clients as they are.
+const EncodingGzip = "gzip"
+
+// markers stored in front of every entry written by Compressed
+const (
+	entryRaw  byte = 0
+	entryGzip byte = 1
+)
+
+// errInvalidEntry is returned for entries not written by Compressed, e.g. entries a
+// persistent backend kept from before the compression was enabled.
+var errInvalidEntry = errors.New("entry is not compressed by cache.Compressed")
+
+// EncodedClient is implemented by caches which keep entries compressed.
+type EncodedClient interface {
+	// GetEncoded returns the first line of the entry and the rest of it as stored,
+	// encoding is EncodingGzip when the rest is compressed and empty otherwise
+	GetEncoded(key string) (head, body []byte, encoding string, err error)
+}
+
+// Compressed gzips the entries of client which are at least threshold bytes long. The
+// first line of an entry is kept uncompressed, Typed stores the freshness of a value
+// there, so the value can be served to clients accepting gzip without decompressing it.
+type Compressed struct {
+	client    CacheClientInterface
+	threshold int
+}
+
+func NewCompressed(client CacheClientInterface, threshold int) Compressed {
+	return Compressed{
+		client:    client,
+		threshold: threshold,
+	}
+}
+
+var gzipWriters = sync.Pool{
+	New: func() any { return gzip.NewWriter(io.Discard) },
+}
+
+func (c Compressed) Get(key string) ([]byte, error) {
+	head, body, encoding, err := c.GetEncoded(key)
+	if err != nil {
+		return nil, err
+	}
+	// head shares its array with body, the capacity is limited so it is copied on append
+	head = head[:len(head):len(head)]
+	if encoding != EncodingGzip {
+		return append(head, body...), nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	entry := bytes.NewBuffer(head)
+	_, err = entry.ReadFrom(reader)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Bytes(), nil
+}
+
+// GetEncoded returns an entry without decompressing it, see EncodedClient.
+func (c Compressed) GetEncoded(key string) ([]byte, []byte, string, error) {
+	data, err := c.client.Get(key)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if len(data) == 0 {
+		return nil, nil, "", errInvalidEntry
+	}
+
+	marker, data := data[0], data[1:]
+	headSize, n := binary.Uvarint(data)
+	if n <= 0 || headSize > uint64(len(data)-n) {
+		return nil, nil, "", errInvalidEntry
+	}
+	head, body := data[n:n+int(headSize)], data[n+int(headSize):]
+
+	switch marker {
+	case entryRaw:
+		return head, body, "", nil
+	case entryGzip:
+		return head, body, EncodingGzip, nil
+	}
+	return nil, nil, "", errInvalidEntry
+}
+
+func (c Compressed) Set(key string, entry []byte) error {
+	data, err := c.encode(entry)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(key, data)
+}
+
+func (c Compressed) SetWithTTL(key string, entry []byte, ttl time.Duration) error {
+	data, err := c.encode(entry)
+	if err != nil {
+		return err
+	}
+	return c.client.SetWithTTL(key, data, ttl)
+}
+
+func (c Compressed) Delete(key string) error {
+	return c.client.Delete(key)
+}
+
+func (c Compressed) Reset() error {
+	return c.client.Reset()
+}
+
+func (c Compressed) Stats() (Stats, error) {
+	return StatsOf(c.client)
+}
+
+func (c Compressed) DeletePrefix(prefix string) (int, error) {
+	return DeletePrefix(c.client, prefix)
+}
+
+// encode stores the marker and the size of the first line in front of the entry, the
+// rest is compressed unless it is below the threshold or does not get smaller.
+func (c Compressed) encode(entry []byte) ([]byte, error) {
+	head, body := entry[:0], entry
+	if i := bytes.IndexByte(entry, '\n'); i >= 0 {
+		head, body = entry[:i+1], entry[i+1:]
+	}
+
+	data := bytes.NewBuffer(make([]byte, 0, 1+binary.MaxVarintLen64+len(entry)))
+	data.WriteByte(entryGzip)
+	data.Write(binary.AppendUvarint(nil, uint64(len(head))))
+	data.Write(head)
+	prefixSize := data.Len()
+
+	if len(body) >= c.threshold {
+		writer := gzipWriters.Get().(*gzip.Writer)
+		defer gzipWriters.Put(writer)
+
+		writer.Reset(data)
+		_, err := writer.Write(body)
+		if err != nil {
+			return nil, err
+		}
+		err = writer.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if data.Len()-prefixSize < len(body) {
+			return data.Bytes(), nil
+		}
+	}
+
+	raw := data.Bytes()[:prefixSize]
+	raw[0] = entryRaw
+	return append(raw, body...), nil
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompressed(t *testing.T) {
+	backend, err := NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewCompressed(backend, 64)
+
+	large := `{"stored_at":"2023-06-01T10:00:00Z"}` + "\n" + strings.Repeat(`{"title":"news"},`, 100)
+	testCases := []struct {
+		name     string
+		entry    string
+		head     string
+		encoding string
+	}{
+		{
+			name:  "BelowThreshold",
+			entry: "{}\n" + `{"news":[]}`,
+			head:  "{}\n",
+		},
+		{
+			name:     "HeaderKeptUncompressed",
+			entry:    large,
+			head:     `{"stored_at":"2023-06-01T10:00:00Z"}` + "\n",
+			encoding: EncodingGzip,
+		},
+		{
+			name:     "WithoutHeader",
+			entry:    strings.Repeat("news ", 100),
+			encoding: EncodingGzip,
+		},
+		{
+			name:  "Incompressible",
+			entry: "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ!?",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, cache.Set(tc.name, []byte(tc.entry)))
+
+			entry, err := cache.Get(tc.name)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.entry, string(entry))
+
+			head, body, encoding, err := cache.GetEncoded(tc.name)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.head, string(head))
+			assert.Equal(t, tc.encoding, encoding)
+			if encoding == EncodingGzip {
+				stored, err := backend.Get(tc.name)
+				assert.NoError(t, err)
+				assert.Less(t, len(stored), len(tc.entry))
+				assert.Equal(t, tc.entry[len(tc.head):], gunzip(t, body))
+			}
+		})
+	}
+
+	t.Run("UncompressedEntry", func(t *testing.T) {
+		assert.NoError(t, backend.Set("plain", []byte(`{"news":[]}`)))
+		_, err := cache.Get("plain")
+		assert.Error(t, err)
+	})
+}
+
+func gunzip(t *testing.T, data []byte) string {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	bolt "go.etcd.io/bbolt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// diskSweepInterval is how often expired entries are removed from the file.
+	diskSweepInterval = 10 * time.Minute
+	// diskOpenTimeout bounds the wait for the file lock held by another process.
+	diskOpenTimeout = 5 * time.Second
+)
+
+var diskBucket = []byte("entries")
+
+// Disk stores the entries in a bbolt file, so they survive a restart of the service.
+// Like Bigcache every entry starts with its expiry time, zero for entries which never expire.
+type Disk struct {
+	db  *bolt.DB
+	ttl time.Duration
+	now func() time.Time
+
+	counters counters
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDisk opens or creates the cache file at path, entries expire after ttl and zero
+// keeps them until they are deleted. Expired entries are swept in the background until Close.
+func NewDisk(path string, ttl time.Duration) (*Disk, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: diskOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(diskBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	d := &Disk{
+		db:   db,
+		ttl:  ttl,
+		now:  time.Now,
+		stop: make(chan struct{}),
+	}
+	go d.sweepPeriodically()
+	return d, nil
+}
+
+func (d *Disk) Get(key string) ([]byte, error) {
+	entry, err := d.get(key)
+	d.counters.record(err)
+	return entry, err
+}
+
+func (d *Disk) get(key string) ([]byte, error) {
+	var entry []byte
+	err := d.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(diskBucket).Get([]byte(key))
+		if len(data) < expiryHeaderSize || d.expired(data) {
+			return ErrEntryNotFound
+		}
+
+		// data is only valid during the transaction
+		entry = append([]byte(nil), data[expiryHeaderSize:]...)
+		return nil
+	})
+	return entry, err
+}
+
+func (d *Disk) Set(key string, entry []byte) error {
+	return d.SetWithTTL(key, entry, d.ttl)
+}
+
+func (d *Disk) SetWithTTL(key string, entry []byte, ttl time.Duration) error {
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = d.now().Add(ttl).UnixNano()
+	}
+
+	data := make([]byte, expiryHeaderSize+len(entry))
+	binary.BigEndian.PutUint64(data, uint64(expiresAt))
+	copy(data[expiryHeaderSize:], entry)
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskBucket).Put([]byte(key), data)
+	})
+}
+
+func (d *Disk) Delete(key string) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskBucket).Delete([]byte(key))
+	})
+}
+
+func (d *Disk) Reset() error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		err := tx.DeleteBucket(diskBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucket(diskBucket)
+		return err
+	})
+}
+
+func (d *Disk) DeletePrefix(prefix string) (int, error) {
+	var deleted int
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(diskBucket)
+
+		var keys [][]byte
+		cursor := bucket.Cursor()
+		for key, _ := cursor.Seek([]byte(prefix)); key != nil && bytes.HasPrefix(key, []byte(prefix)); key, _ = cursor.Next() {
+			keys = append(keys, append([]byte(nil), key...))
+		}
+
+		for _, key := range keys {
+			err := bucket.Delete(key)
+			if err != nil {
+				return err
+			}
+		}
+		deleted = len(keys)
+		return nil
+	})
+	return deleted, err
+}
+
+// Stats reports the entries including the expired ones not swept yet and the size of the
+// file, evictions count the entries removed by Sweep.
+func (d *Disk) Stats() (Stats, error) {
+	stats := d.counters.stats("disk")
+	err := d.db.View(func(tx *bolt.Tx) error {
+		stats.Entries = int64(tx.Bucket(diskBucket).Stats().KeyN)
+		stats.Bytes = tx.Size()
+		return nil
+	})
+	return stats, err
+}
+
+// Sweep removes the expired entries, their pages are reused by later writes.
+func (d *Disk) Sweep() error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(diskBucket)
+
+		// deleting while iterating skips entries, the keys are collected first
+		var expired [][]byte
+		err := bucket.ForEach(func(key, data []byte) error {
+			if len(data) < expiryHeaderSize || d.expired(data) {
+				expired = append(expired, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range expired {
+			err = bucket.Delete(key)
+			if err != nil {
+				return err
+			}
+		}
+		d.counters.evictions.Add(int64(len(expired)))
+		return nil
+	})
+}
+
+// Close stops the sweeper and closes the file.
+func (d *Disk) Close() error {
+	d.stopOnce.Do(func() { close(d.stop) })
+	return d.db.Close()
+}
+
+func (d *Disk) expired(data []byte) bool {
+	expiresAt := int64(binary.BigEndian.Uint64(data))
+	return expiresAt != 0 && d.now().UnixNano() >= expiresAt
+}
+
+func (d *Disk) sweepPeriodically() {
+	ticker := time.NewTicker(diskSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			// a failed sweep is retried with the next tick
+			_ = d.Sweep()
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "news.db")
+
+	cache, err := NewDisk(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	_, err = cache.Get("missing")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+
+	assert.NoError(t, cache.Set("/news", []byte("news")))
+	assert.NoError(t, cache.SetWithTTL("/article", []byte("article"), time.Minute))
+	assert.NoError(t, cache.SetWithTTL("/forever", []byte("forever"), 0))
+	assert.NoError(t, cache.Set("/deleted", []byte("deleted")))
+	assert.NoError(t, cache.Delete("/deleted"))
+	assert.NoError(t, cache.Delete("/deleted"))
+
+	// the entries survive a restart
+	assert.NoError(t, cache.Close())
+	cache, err = NewDisk(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	cache.now = func() time.Time { return now }
+
+	entry, err := cache.Get("/news")
+	assert.NoError(t, err)
+	assert.Equal(t, "news", string(entry))
+	_, err = cache.Get("/deleted")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+
+	now = now.Add(2 * time.Minute)
+	_, err = cache.Get("/article")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+	entry, err = cache.Get("/news")
+	assert.NoError(t, err)
+	assert.Equal(t, "news", string(entry))
+
+	// sweeping removes the expired entries only
+	now = now.Add(2 * time.Hour)
+	assert.NoError(t, cache.Sweep())
+	assert.Equal(t, []string{"/forever"}, diskKeys(t, cache))
+
+	assert.NoError(t, cache.Reset())
+	assert.Empty(t, diskKeys(t, cache))
+	assert.NoError(t, cache.Set("/news", []byte("news")))
+	assert.Equal(t, []string{"/news"}, diskKeys(t, cache))
+}
+
+func diskKeys(t *testing.T, cache *Disk) []string {
+	var keys []string
+	err := cache.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskBucket).ForEach(func(key, _ []byte) error {
+			keys = append(keys, string(key))
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strings"
+	"time"
+)
+
+// resetBatchSize is the number of keys scanned and deleted at once by Reset and DeletePrefix.
+const resetBatchSize = 500
+
+// Redis stores the entries in a redis server shared by every replica of the service,
+// all keys are namespaced by prefix so the server can be shared with other applications.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+	// counters is shared by the copies of the value, they only cover this replica
+	counters *counters
+}
+
+// NewRedis returns a cache whose entries expire after ttl, zero keeps them until they are evicted.
+func NewRedis(client redis.UniversalClient, prefix string, ttl time.Duration) Redis {
+	return Redis{
+		client:   client,
+		prefix:   prefix,
+		ttl:      ttl,
+		counters: &counters{},
+	}
+}
+
+func (r Redis) Get(key string) ([]byte, error) {
+	entry, err := r.client.Get(context.Background(), r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		err = ErrEntryNotFound
+	}
+	r.counters.record(err)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r Redis) Set(key string, entry []byte) error {
+	return r.SetWithTTL(key, entry, r.ttl)
+}
+
+// SetWithTTL stores an entry which expires after ttl instead of the default lifetime.
+func (r Redis) SetWithTTL(key string, entry []byte, ttl time.Duration) error {
+	return r.client.Set(context.Background(), r.prefix+key, entry, ttl).Err()
+}
+
+func (r Redis) Delete(key string) error {
+	return r.client.Del(context.Background(), r.prefix+key).Err()
+}
+
+// Reset deletes every entry under the prefix, keys of other applications are kept.
+func (r Redis) Reset() error {
+	_, err := r.DeletePrefix("")
+	return err
+}
+
+func (r Redis) DeletePrefix(prefix string) (int, error) {
+	ctx := context.Background()
+	iter := r.scan(ctx, prefix)
+
+	var deleted int
+	keys := make([]string, 0, resetBatchSize)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == resetBatchSize {
+			if err := r.client.Del(ctx, keys...).Err(); err != nil {
+				return deleted, err
+			}
+			deleted += len(keys)
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, err
+	}
+
+	if len(keys) > 0 {
+		if err := r.client.Del(ctx, keys...).Err(); err != nil {
+			return deleted, err
+		}
+		deleted += len(keys)
+	}
+	return deleted, nil
+}
+
+// Stats counts the entries under the prefix, redis does not report the memory of single keys.
+// Hits and misses are the ones of this replica, evictions are not known.
+func (r Redis) Stats() (Stats, error) {
+	ctx := context.Background()
+	stats := r.counters.stats("redis")
+
+	iter := r.scan(ctx, "")
+	for iter.Next(ctx) {
+		stats.Entries++
+	}
+	return stats, iter.Err()
+}
+
+// scan iterates the keys starting with prefix, the prefix is matched literally.
+func (r Redis) scan(ctx context.Context, prefix string) *redis.ScanIterator {
+	return r.client.Scan(ctx, 0, globEscaper.Replace(r.prefix+prefix)+"*", resetBatchSize).Iterator()
+}
+
+// globEscaper escapes the characters redis treats as patterns, article URLs often contain "?".
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
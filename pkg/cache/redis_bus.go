@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBus fans out invalidations through a redis pub/sub channel.
+type RedisBus struct {
+	client  redis.UniversalClient
+	channel string
+	// origin identifies the replica, it ignores the invalidations it published itself
+	origin string
+}
+
+type busMessage struct {
+	Origin string `json:"origin"`
+	Invalidation
+}
+
+func NewRedisBus(client redis.UniversalClient, channel string) (RedisBus, error) {
+	origin := make([]byte, 8)
+	_, err := rand.Read(origin)
+	if err != nil {
+		return RedisBus{}, err
+	}
+
+	return RedisBus{
+		client:  client,
+		channel: channel,
+		origin:  hex.EncodeToString(origin),
+	}, nil
+}
+
+func (b RedisBus) Publish(ctx context.Context, invalidation Invalidation) error {
+	message, err := json.Marshal(busMessage{Origin: b.origin, Invalidation: invalidation})
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, message).Err()
+}
+
+// Subscribe returns once the subscription is active, the invalidations are handled in
+// the background until ctx is done.
+func (b RedisBus) Subscribe(ctx context.Context, handle func(Invalidation)) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	_, err := pubsub.Receive(ctx)
+	if err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	go func() {
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var message busMessage
+				if json.Unmarshal([]byte(msg.Payload), &message) != nil || message.Origin == b.origin {
+					continue
+				}
+				handle(message.Invalidation)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	cache := NewRedis(client, "news:", time.Minute)
+	// a second replica sharing the same server
+	replica := NewRedis(client, "news:", time.Minute)
+
+	t.Run("Miss", func(t *testing.T) {
+		_, err := cache.Get("missing")
+		assert.ErrorIs(t, err, ErrEntryNotFound)
+	})
+
+	t.Run("SharedBetweenReplicas", func(t *testing.T) {
+		assert.NoError(t, cache.Set("/news", []byte(`{"news":[]}`)))
+
+		entry, err := replica.Get("/news")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"news":[]}`, string(entry))
+		assert.True(t, server.Exists("news:/news"))
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		assert.NoError(t, cache.Set("default", []byte("1")))
+		assert.NoError(t, cache.SetWithTTL("short", []byte("2"), time.Second))
+		assert.Equal(t, time.Minute, server.TTL("news:default"))
+		assert.Equal(t, time.Second, server.TTL("news:short"))
+
+		server.FastForward(2 * time.Second)
+		_, err := cache.Get("short")
+		assert.ErrorIs(t, err, ErrEntryNotFound)
+		_, err = cache.Get("default")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.NoError(t, cache.Set("deleted", []byte("1")))
+		assert.NoError(t, cache.Delete("deleted"))
+		_, err := cache.Get("deleted")
+		assert.ErrorIs(t, err, ErrEntryNotFound)
+		// deleting a missing entry is not an error
+		assert.NoError(t, cache.Delete("deleted"))
+	})
+
+	t.Run("ResetKeepsOtherPrefixes", func(t *testing.T) {
+		other := NewRedis(client, "other:", 0)
+		assert.NoError(t, other.Set("key", []byte("kept")))
+		for i := 0; i < resetBatchSize+10; i++ {
+			assert.NoError(t, cache.Set(time.Duration(i).String(), []byte("1")))
+		}
+
+		assert.NoError(t, cache.Reset())
+		assert.Equal(t, []string{"other:key"}, server.Keys())
+	})
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Invalidation tells the replicas to drop Key or the keys starting with Prefix from their
+// L1, or every entry on Reset.
+type Invalidation struct {
+	Key    string `json:"key,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Reset  bool   `json:"reset,omitempty"`
+}
+
+// InvalidationBus fans out invalidations between the replicas sharing an L2.
+type InvalidationBus interface {
+	Publish(ctx context.Context, invalidation Invalidation) error
+	// Subscribe calls handle for every invalidation published by another replica until ctx is done
+	Subscribe(ctx context.Context, handle func(Invalidation)) error
+}
+
+// TwoTier answers hot keys from an in memory L1 in front of a shared L2. Writes go
+// through to both tiers, L2 hits are back-filled into L1 and every write is fanned out
+// to the other replicas so they drop their outdated L1 copy.
+type TwoTier struct {
+	l1    CacheClientInterface
+	l2    CacheClientInterface
+	l1TTL time.Duration
+	bus   InvalidationBus
+}
+
+// NewTwoTier keeps entries in l1 for at most l1TTL, bus may be nil when the L2 is not
+// shared with other replicas. The subscription to the bus ends with ctx.
+func NewTwoTier(ctx context.Context, l1, l2 CacheClientInterface, l1TTL time.Duration, bus InvalidationBus) (*TwoTier, error) {
+	t := &TwoTier{
+		l1:    l1,
+		l2:    l2,
+		l1TTL: l1TTL,
+		bus:   bus,
+	}
+
+	if bus != nil {
+		err := bus.Subscribe(ctx, t.invalidate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *TwoTier) Get(key string) ([]byte, error) {
+	entry, err := t.l1.Get(key)
+	if err == nil {
+		return entry, nil
+	}
+
+	entry, err = t.l2.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// a failing L1 only costs the next read another trip to L2
+	_ = t.l1.SetWithTTL(key, entry, t.l1TTL)
+	return entry, nil
+}
+
+func (t *TwoTier) Set(key string, entry []byte) error {
+	err := t.l2.Set(key, entry)
+	if err != nil {
+		return err
+	}
+	return t.writeL1(key, entry, t.l1TTL)
+}
+
+func (t *TwoTier) SetWithTTL(key string, entry []byte, ttl time.Duration) error {
+	err := t.l2.SetWithTTL(key, entry, ttl)
+	if err != nil {
+		return err
+	}
+
+	if ttl <= 0 || ttl > t.l1TTL {
+		ttl = t.l1TTL
+	}
+	return t.writeL1(key, entry, ttl)
+}
+
+func (t *TwoTier) Delete(key string) error {
+	err := t.l2.Delete(key)
+	if err != nil {
+		return err
+	}
+	return errors.Join(t.l1.Delete(key), t.publish(Invalidation{Key: key}))
+}
+
+func (t *TwoTier) Reset() error {
+	err := t.l2.Reset()
+	if err != nil {
+		return err
+	}
+	return errors.Join(t.l1.Reset(), t.publish(Invalidation{Reset: true}))
+}
+
+// DeletePrefix deletes the keys starting with prefix from both tiers and returns the number
+// of entries deleted from L2, which holds every entry of L1.
+func (t *TwoTier) DeletePrefix(prefix string) (int, error) {
+	deleted, err := DeletePrefix(t.l2, prefix)
+	if err != nil {
+		return deleted, err
+	}
+
+	_, err = DeletePrefix(t.l1, prefix)
+	return deleted, errors.Join(err, t.publish(Invalidation{Prefix: prefix}))
+}
+
+// Stats reports the stats of both tiers, a hit of either tier counts as hit and a miss
+// of L2 as miss.
+func (t *TwoTier) Stats() (Stats, error) {
+	l1, err := StatsOf(t.l1)
+	if err != nil {
+		return Stats{}, err
+	}
+	l2, err := StatsOf(t.l2)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	return Stats{
+		Backend:   "two-tier",
+		Hits:      l1.Hits + l2.Hits,
+		Misses:    l2.Misses,
+		Evictions: l1.Evictions + l2.Evictions,
+		Entries:   l2.Entries,
+		Bytes:     l1.Bytes + l2.Bytes,
+		Tiers:     []Stats{l1, l2},
+	}, nil
+}
+
+func (t *TwoTier) writeL1(key string, entry []byte, ttl time.Duration) error {
+	return errors.Join(t.l1.SetWithTTL(key, entry, ttl), t.publish(Invalidation{Key: key}))
+}
+
+func (t *TwoTier) publish(invalidation Invalidation) error {
+	if t.bus == nil {
+		return nil
+	}
+	return t.bus.Publish(context.Background(), invalidation)
+}
+
+// invalidate drops an entry another replica changed, the next read fetches it from L2.
+func (t *TwoTier) invalidate(invalidation Invalidation) {
+	switch {
+	case invalidation.Reset:
+		_ = t.l1.Reset()
+	case invalidation.Prefix != "":
+		_, _ = DeletePrefix(t.l1, invalidation.Prefix)
+	default:
+		_ = t.l1.Delete(invalidation.Key)
+	}
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTwoTier(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// newReplica returns the cache of one replica with its own L1 and the shared L2
+	newReplica := func() (*TwoTier, CacheClientInterface) {
+		l1, err := NewBigcache(time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus, err := NewRedisBus(client, "news:invalidations")
+		if err != nil {
+			t.Fatal(err)
+		}
+		replica, err := NewTwoTier(ctx, l1, NewRedis(client, "news:", time.Hour), time.Minute, bus)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return replica, l1
+	}
+	first, firstL1 := newReplica()
+	second, secondL1 := newReplica()
+
+	t.Run("WriteThrough", func(t *testing.T) {
+		assert.NoError(t, first.SetWithTTL("/news", []byte("v1"), 2*time.Hour))
+
+		entry, err := firstL1.Get("/news")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", string(entry))
+		assert.Equal(t, 2*time.Hour, server.TTL("news:/news"))
+	})
+
+	t.Run("BackFill", func(t *testing.T) {
+		_, err := secondL1.Get("/news")
+		assert.ErrorIs(t, err, ErrEntryNotFound)
+
+		entry, err := second.Get("/news")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", string(entry))
+
+		// the next read is answered from memory even when L2 lost the entry
+		server.Del("news:/news")
+		entry, err = second.Get("/news")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", string(entry))
+	})
+
+	t.Run("InvalidationFanOut", func(t *testing.T) {
+		assert.NoError(t, first.Set("/news", []byte("v2")))
+		assert.Eventually(t, func() bool {
+			entry, err := second.Get("/news")
+			return err == nil && string(entry) == "v2"
+		}, time.Second, time.Millisecond)
+
+		// the replica which wrote the entry keeps its own copy
+		entry, err := firstL1.Get("/news")
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", string(entry))
+
+		assert.NoError(t, second.Delete("/news"))
+		assert.Eventually(t, func() bool {
+			_, err := firstL1.Get("/news")
+			return err == ErrEntryNotFound
+		}, time.Second, time.Millisecond)
+		_, err = first.Get("/news")
+		assert.ErrorIs(t, err, ErrEntryNotFound)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		assert.NoError(t, first.Set("/article", []byte("article")))
+		_, err := second.Get("/article")
+		assert.NoError(t, err)
+
+		assert.NoError(t, first.Reset())
+		assert.Eventually(t, func() bool {
+			_, err := secondL1.Get("/article")
+			return err == ErrEntryNotFound
+		}, time.Second, time.Millisecond)
+	})
+}
@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/fir1/news/pkg/coalesce"
+	"time"
+)
+
+// refreshTimeout bounds a background refresh of a stale entry, it has no caller whose
+// context could end it.
+const refreshTimeout = time.Minute
+
+type Status string
+
+const (
+	// StatusHit is reported for a value within its TTL.
+	StatusHit Status = "HIT"
+	// StatusStale is reported for a value past its TTL but within the max stale period.
+	StatusStale Status = "STALE"
+	// StatusMiss is reported for a value which had to be loaded.
+	StatusMiss Status = "MISS"
+)
+
+// Meta describes the freshness of a value returned by the cache.
+type Meta struct {
+	Status Status
+	// Age is the time since the value was loaded, zero for a miss
+	Age time.Duration
+	// FreshFor is the time left until the value turns stale, zero once it is stale and for
+	// a loaded value which is not cacheable
+	FreshFor time.Duration
+}
+
+// header is stored as the first line of every entry, so the freshness of an entry is
+// known independently of how long the backend keeps it. The JSON of the value follows
+// on the next line and can be written to a response without decoding it.
+type header struct {
+	StoredAt   time.Time `json:"stored_at"`
+	FreshUntil time.Time `json:"fresh_until"`
+}
+
+// Encoded is the JSON of a cached value as stored by the cache client.
+type Encoded struct {
+	Body []byte
+	// Encoding is EncodingGzip when Body is compressed, and empty otherwise
+	Encoding string
+}
+
+// Typed stores values of T as JSON, so callers do not repeat the (de)serialisation.
+type Typed[T any] struct {
+	client    CacheClientInterface
+	ttl       time.Duration
+	maxStale  time.Duration
+	cacheable func(T) bool
+	loads     *coalesce.Group[T]
+	now       func() time.Time
+}
+
+// NewTyped returns a typed view of client whose entries are fresh for ttl.
+func NewTyped[T any](client CacheClientInterface, ttl time.Duration) Typed[T] {
+	return Typed[T]{
+		client: client,
+		ttl:    ttl,
+		loads:  &coalesce.Group[T]{},
+		now:    time.Now,
+	}
+}
+
+// WithMaxStale keeps entries for maxStale past their TTL, GetOrLoad serves them right
+// away while it refreshes them in the background, and keeps serving them while the
+// refresh fails.
+func (t Typed[T]) WithMaxStale(maxStale time.Duration) Typed[T] {
+	t.maxStale = maxStale
+	return t
+}
+
+// WithCacheable lets GetOrLoad store only the loaded values for which cacheable is true.
+func (t Typed[T]) WithCacheable(cacheable func(T) bool) Typed[T] {
+	t.cacheable = cacheable
+	return t
+}
+
+// Get returns the fresh or stale value of key.
+func (t Typed[T]) Get(key string) (T, error) {
+	value, _, err := t.GetWithMeta(key)
+	return value, err
+}
+
+// GetWithMeta returns the value of key together with its freshness, entries past the
+// max stale period are reported as ErrEntryNotFound.
+func (t Typed[T]) GetWithMeta(key string) (T, Meta, error) {
+	var value T
+	entry, err := t.client.Get(key)
+	if err != nil {
+		return value, Meta{}, err
+	}
+
+	head, body, ok := bytes.Cut(entry, []byte("\n"))
+	if !ok {
+		// entries of an older format are loaded again
+		return value, Meta{}, ErrEntryNotFound
+	}
+	meta, err := t.meta(head)
+	if err != nil {
+		return value, Meta{}, err
+	}
+
+	err = json.Unmarshal(body, &value)
+	return value, meta, err
+}
+
+// GetEncoded returns the JSON of the value of key without decoding it, still compressed
+// when the client keeps it compressed, so it can be written to a response as is.
+func (t Typed[T]) GetEncoded(key string) (Encoded, Meta, error) {
+	encodedClient, ok := t.client.(EncodedClient)
+	if !ok {
+		entry, err := t.client.Get(key)
+		if err != nil {
+			return Encoded{}, Meta{}, err
+		}
+		head, body, ok := bytes.Cut(entry, []byte("\n"))
+		if !ok {
+			return Encoded{}, Meta{}, ErrEntryNotFound
+		}
+		meta, err := t.meta(head)
+		return Encoded{Body: body}, meta, err
+	}
+
+	head, body, encoding, err := encodedClient.GetEncoded(key)
+	if err != nil {
+		return Encoded{}, Meta{}, err
+	}
+	meta, err := t.meta(bytes.TrimSuffix(head, []byte("\n")))
+	return Encoded{Body: body, Encoding: encoding}, meta, err
+}
+
+// meta reports the freshness of an entry from its header.
+func (t Typed[T]) meta(head []byte) (Meta, error) {
+	var h header
+	err := json.Unmarshal(head, &h)
+	if err != nil {
+		return Meta{}, ErrEntryNotFound
+	}
+
+	now := t.now()
+	meta := Meta{Status: StatusHit, Age: now.Sub(h.StoredAt)}
+	if meta.Age < 0 {
+		meta.Age = 0
+	}
+	switch {
+	case now.Before(h.FreshUntil):
+		meta.FreshFor = h.FreshUntil.Sub(now)
+	case now.Before(h.FreshUntil.Add(t.maxStale)):
+		meta.Status = StatusStale
+	default:
+		return Meta{}, ErrEntryNotFound
+	}
+	return meta, nil
+}
+
+func (t Typed[T]) Set(key string, value T) error {
+	return t.SetWithTTL(key, value, t.ttl)
+}
+
+// SetWithTTL stores a value which is fresh for ttl, it is kept for the max stale period on top.
+func (t Typed[T]) SetWithTTL(key string, value T, ttl time.Duration) error {
+	now := t.now()
+	head, err := json.Marshal(header{
+		StoredAt:   now,
+		FreshUntil: now.Add(ttl),
+	})
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	// json.Marshal escapes new lines, the first one ends the header
+	entry := make([]byte, 0, len(head)+1+len(body))
+	entry = append(append(append(entry, head...), '\n'), body...)
+	return t.client.SetWithTTL(key, entry, ttl+t.maxStale)
+}
+
+// GetOrLoad returns the cached value of key, on a miss the value is loaded and stored
+// and a stale value is returned while it is refreshed in the background. Concurrent
+// loads of the same key are shared, and like the package level GetOrLoad an entry
+// which can not be read or stored never fails the caller.
+func (t Typed[T]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, Meta, error) {
+	value, meta, err := t.GetWithMeta(key)
+	if err == nil {
+		if meta.Status == StatusStale {
+			go t.refresh(key, load)
+		}
+		return value, meta, nil
+	}
+
+	value, err = t.load(ctx, key, 0, load)
+	if err != nil {
+		return value, Meta{Status: StatusMiss}, err
+	}
+
+	meta = Meta{Status: StatusMiss}
+	if t.cacheable == nil || t.cacheable(value) {
+		meta.FreshFor = t.ttl
+	}
+	return value, meta, nil
+}
+
+// Warm loads and stores the value of key unless it stays fresh for more than margin, a
+// warmer running every margin keeps the entry from ever turning stale.
+func (t Typed[T]) Warm(ctx context.Context, key string, margin time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	value, meta, err := t.GetWithMeta(key)
+	if err == nil && meta.FreshFor > margin {
+		return value, nil
+	}
+	return t.load(ctx, key, margin, load)
+}
+
+func (t Typed[T]) refresh(key string, load func(ctx context.Context) (T, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	// a failed refresh keeps the stale value, the next request tries again
+	_, _ = t.load(ctx, key, 0, load)
+}
+
+// load loads and stores the value of key unless it stays fresh for more than margin.
+func (t Typed[T]) load(ctx context.Context, key string, margin time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	return t.loads.Do(ctx, key, func(ctx context.Context) (T, error) {
+		// the entry may have been loaded while this caller waited for its turn
+		value, meta, err := t.GetWithMeta(key)
+		if err == nil && meta.FreshFor > margin {
+			return value, nil
+		}
+
+		value, err = load(ctx)
+		if err != nil {
+			return value, err
+		}
+
+		if t.cacheable == nil || t.cacheable(value) {
+			_ = t.Set(key, value)
+		}
+		return value, nil
+	})
+}
@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type article struct {
+	Title    string   `json:"title"`
+	Keywords []string `json:"keywords"`
+}
+
+func TestTyped(t *testing.T) {
+	client, err := NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	articles := NewTyped[article](client, time.Minute)
+
+	ctx := context.Background()
+	loads := 0
+	load := func(ctx context.Context) (article, error) {
+		loads++
+		return article{Title: "Title", Keywords: []string{"news"}}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, meta, err := articles.GetOrLoad(ctx, "/article?url=1", load)
+		assert.NoError(t, err)
+		assert.Equal(t, article{Title: "Title", Keywords: []string{"news"}}, value)
+		if i == 0 {
+			assert.Equal(t, StatusMiss, meta.Status)
+		} else {
+			assert.Equal(t, StatusHit, meta.Status)
+		}
+	}
+	assert.Equal(t, 1, loads)
+
+	// failed loads are not cached
+	loadErr := errors.New("upstream failed")
+	_, _, err = articles.GetOrLoad(ctx, "/article?url=2", func(ctx context.Context) (article, error) { return article{}, loadErr })
+	assert.ErrorIs(t, err, loadErr)
+	_, err = articles.Get("/article?url=2")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+
+	assert.NoError(t, articles.Set("/article?url=3", article{Title: "Set"}))
+	value, err := articles.Get("/article?url=3")
+	assert.NoError(t, err)
+	assert.Equal(t, "Set", value.Title)
+
+	// entries which are not valid JSON of T are reported
+	assert.NoError(t, client.Set("/article?url=4", []byte("not json")))
+	_, err = articles.Get("/article?url=4")
+	assert.Error(t, err)
+
+	// and replaced by loading them again
+	value, _, err = articles.GetOrLoad(ctx, "/article?url=4", load)
+	assert.NoError(t, err)
+	assert.Equal(t, "Title", value.Title)
+	assert.Equal(t, 2, loads)
+
+	// values which are not cacheable are returned but not stored
+	partial := articles.WithCacheable(func(a article) bool { return len(a.Keywords) > 0 })
+	_, _, err = partial.GetOrLoad(ctx, "/article?url=5", func(ctx context.Context) (article, error) {
+		return article{Title: "Partial"}, nil
+	})
+	assert.NoError(t, err)
+	_, err = articles.Get("/article?url=5")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+}
+
+func TestTypedStaleWhileRevalidate(t *testing.T) {
+	client, err := NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the clock is read by the background refreshes as well
+	var clock atomic.Int64
+	clock.Store(time.Now().UnixNano())
+	advance := func(d time.Duration) { clock.Add(int64(d)) }
+
+	articles := NewTyped[article](client, time.Minute).WithMaxStale(10 * time.Minute)
+	articles.now = func() time.Time { return time.Unix(0, clock.Load()) }
+
+	ctx := context.Background()
+	var loads atomic.Int32
+	var fail atomic.Bool
+	load := func(ctx context.Context) (article, error) {
+		n := loads.Add(1)
+		if fail.Load() {
+			return article{}, errors.New("upstream failed")
+		}
+		return article{Title: fmt.Sprintf("Version %d", n)}, nil
+	}
+	cachedTitle := func() string {
+		value, _ := articles.Get("key")
+		return value.Title
+	}
+
+	value, meta, err := articles.GetOrLoad(ctx, "key", load)
+	assert.NoError(t, err)
+	assert.Equal(t, Meta{Status: StatusMiss, FreshFor: time.Minute}, meta)
+	assert.Equal(t, "Version 1", value.Title)
+
+	advance(30 * time.Second)
+	_, meta, err = articles.GetOrLoad(ctx, "key", load)
+	assert.NoError(t, err)
+	assert.Equal(t, Meta{Status: StatusHit, Age: 30 * time.Second, FreshFor: 30 * time.Second}, meta)
+
+	// a stale value is served right away while it is refreshed in the background
+	advance(2 * time.Minute)
+	value, meta, err = articles.GetOrLoad(ctx, "key", load)
+	assert.NoError(t, err)
+	assert.Equal(t, Meta{Status: StatusStale, Age: 150 * time.Second}, meta)
+	assert.Equal(t, "Version 1", value.Title)
+	assert.Eventually(t, func() bool { return cachedTitle() == "Version 2" }, time.Second, time.Millisecond)
+
+	_, meta, err = articles.GetOrLoad(ctx, "key", load)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusHit, meta.Status)
+
+	// while the upstream fails the stale value keeps being served until max stale
+	fail.Store(true)
+	advance(5 * time.Minute)
+	value, meta, err = articles.GetOrLoad(ctx, "key", load)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusStale, meta.Status)
+	assert.Equal(t, "Version 2", value.Title)
+	assert.Eventually(t, func() bool { return loads.Load() == 3 }, time.Second, time.Millisecond)
+
+	advance(10 * time.Minute)
+	_, _, err = articles.GetOrLoad(ctx, "key", load)
+	assert.Error(t, err)
+	assert.Equal(t, int32(4), loads.Load())
+}
+
+func TestTypedWarm(t *testing.T) {
+	client, err := NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	articles := NewTyped[article](client, 5*time.Minute).WithMaxStale(time.Hour)
+	articles.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	loads := 0
+	load := func(ctx context.Context) (article, error) {
+		loads++
+		return article{Title: fmt.Sprintf("Version %d", loads)}, nil
+	}
+
+	value, err := articles.Warm(ctx, "key", time.Minute, load)
+	assert.NoError(t, err)
+	assert.Equal(t, "Version 1", value.Title)
+
+	// an entry staying fresh past the next run of the warmer is kept
+	now = now.Add(3 * time.Minute)
+	value, err = articles.Warm(ctx, "key", time.Minute, load)
+	assert.NoError(t, err)
+	assert.Equal(t, "Version 1", value.Title)
+
+	// an entry turning stale before the next run is refreshed while it is still fresh
+	now = now.Add(90 * time.Second)
+	value, err = articles.Warm(ctx, "key", time.Minute, load)
+	assert.NoError(t, err)
+	assert.Equal(t, "Version 2", value.Title)
+
+	_, meta, err := articles.GetWithMeta("key")
+	assert.NoError(t, err)
+	assert.Equal(t, Meta{Status: StatusHit, FreshFor: 5 * time.Minute}, meta)
+}
+
+func TestTypedGetEncoded(t *testing.T) {
+	backend, err := NewBigcache(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := article{Title: "Title", Keywords: []string{"news", "uk"}}
+	for _, client := range []CacheClientInterface{backend, NewCompressed(backend, 0)} {
+		articles := NewTyped[article](client, time.Minute)
+		assert.NoError(t, articles.Set("key", value))
+
+		encoded, meta, err := articles.GetEncoded("key")
+		assert.NoError(t, err)
+		assert.Equal(t, StatusHit, meta.Status)
+
+		body := encoded.Body
+		if encoded.Encoding == EncodingGzip {
+			body = []byte(gunzip(t, body))
+		}
+		assert.JSONEq(t, `{"title":"Title","keywords":["news","uk"]}`, string(body))
+
+		cached, err := articles.Get("key")
+		assert.NoError(t, err)
+		assert.Equal(t, value, cached)
+	}
+}
@@ -0,0 +1,245 @@
+// Package circuitbreaker stops calling an upstream which keeps failing and probes it
+// again after a cool-down period.
+package circuitbreaker
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned by Allow while the breaker is open or the half-open probes are in flight.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// idleBreakerTTL is how long a breaker without calls is kept by a Registry.
+const idleBreakerTTL = 10 * time.Minute
+
+type State string
+
+const (
+	// StateClosed lets every call through and counts consecutive failures.
+	StateClosed State = "closed"
+	// StateOpen rejects every call until OpenTimeout elapsed.
+	StateOpen State = "open"
+	// StateHalfOpen lets a limited number of probe calls through, a success closes the breaker again.
+	StateHalfOpen State = "half-open"
+)
+
+type Config struct {
+	// FailureThreshold is the number of consecutive failures which opens the breaker
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before probing the upstream again
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of concurrent probe calls while half-open
+	HalfOpenMaxRequests int
+}
+
+// Snapshot describes the state of a breaker at a point in time.
+type Snapshot struct {
+	Name                string
+	State               State
+	ConsecutiveFailures int
+	OpenedAt            time.Time
+	LastError           string
+	LastFailureAt       time.Time
+}
+
+type Breaker struct {
+	name string
+	cnf  Config
+	now  func() time.Time
+
+	mu                  sync.Mutex
+	state               State
+	consecutiveFailures int
+	openedAt            time.Time
+	halfOpenInFlight    int
+	lastError           string
+	lastFailureAt       time.Time
+	lastUsed            time.Time
+}
+
+func New(name string, cnf Config) *Breaker {
+	if cnf.FailureThreshold <= 0 {
+		cnf.FailureThreshold = 1
+	}
+	if cnf.HalfOpenMaxRequests <= 0 {
+		cnf.HalfOpenMaxRequests = 1
+	}
+
+	return &Breaker{
+		name:     name,
+		cnf:      cnf,
+		now:      time.Now,
+		state:    StateClosed,
+		lastUsed: time.Now(),
+	}
+}
+
+// Allow reports whether a call may proceed, every allowed call must be followed by
+// exactly one call of Success or Failure.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastUsed = b.now()
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cnf.OpenTimeout {
+		b.state = StateHalfOpen
+		b.halfOpenInFlight = 0
+	}
+
+	switch b.state {
+	case StateOpen:
+		return ErrOpen
+	case StateHalfOpen:
+		if b.halfOpenInFlight >= b.cnf.HalfOpenMaxRequests {
+			return ErrOpen
+		}
+		b.halfOpenInFlight++
+	}
+	return nil
+}
+
+// Success records a successful call and closes the breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.consecutiveFailures = 0
+	b.halfOpenInFlight = 0
+	b.lastUsed = b.now()
+}
+
+// Failure records a failed call, the breaker opens once the threshold is reached or
+// immediately when a half-open probe fails.
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.lastUsed = now
+	b.consecutiveFailures++
+	b.lastFailureAt = now
+	if err != nil {
+		b.lastError = err.Error()
+	}
+
+	if b.state == StateHalfOpen || b.consecutiveFailures >= b.cnf.FailureThreshold {
+		b.state = StateOpen
+		b.openedAt = now
+		b.halfOpenInFlight = 0
+	}
+}
+
+// Cancel gives back an allowed call which ended without telling anything about the
+// upstream, e.g. because the caller went away.
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+	b.lastUsed = b.now()
+}
+
+// idle reports whether the breaker had no calls for idleBreakerTTL and forgetting it
+// would not let calls through which it still rejects.
+func (b *Breaker) idle(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.halfOpenInFlight > 0 || now.Sub(b.lastUsed) <= idleBreakerTTL {
+		return false
+	}
+	return b.state != StateOpen || now.Sub(b.openedAt) >= b.cnf.OpenTimeout
+}
+
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == StateOpen && b.now().Sub(b.openedAt) >= b.cnf.OpenTimeout {
+		// the next call will probe the upstream
+		state = StateHalfOpen
+	}
+
+	return Snapshot{
+		Name:                b.name,
+		State:               state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		OpenedAt:            b.openedAt,
+		LastError:           b.lastError,
+		LastFailureAt:       b.lastFailureAt,
+	}
+}
+
+// Registry keeps one breaker per upstream, e.g. per feed URL.
+type Registry struct {
+	cnf Config
+	now func() time.Time
+
+	mu        sync.Mutex
+	breakers  map[string]*Breaker
+	lastPrune time.Time
+}
+
+func NewRegistry(cnf Config) *Registry {
+	return &Registry{
+		cnf:       cnf,
+		now:       time.Now,
+		breakers:  make(map[string]*Breaker),
+		lastPrune: time.Now(),
+	}
+}
+
+// Get returns the breaker of name and creates it on first use.
+func (r *Registry) Get(name string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if now.Sub(r.lastPrune) > idleBreakerTTL {
+		r.prune(now)
+	}
+
+	b, ok := r.breakers[name]
+	if !ok {
+		b = New(name, r.cnf)
+		b.now = r.now
+		b.lastUsed = now
+		r.breakers[name] = b
+	}
+	return b
+}
+
+// prune forgets idle breakers, they are keyed by the feed URLs clients ask for and the
+// map would grow forever otherwise.
+func (r *Registry) prune(now time.Time) {
+	for name, b := range r.breakers {
+		if b.idle(now) {
+			delete(r.breakers, name)
+		}
+	}
+	r.lastPrune = now
+}
+
+// Snapshot returns the state of every breaker sorted by name.
+func (r *Registry) Snapshot() []Snapshot {
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	snapshots := make([]Snapshot, len(breakers))
+	for i, b := range breakers {
+		snapshots[i] = b.Snapshot()
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots
+}
@@ -0,0 +1,102 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2023, 7, 25, 12, 0, 0, 0, time.UTC)
+	breaker := New("http://feeds.bbci.co.uk/news/uk/rss.xml", Config{
+		FailureThreshold:    2,
+		OpenTimeout:         30 * time.Second,
+		HalfOpenMaxRequests: 1,
+	})
+	breaker.now = func() time.Time { return now }
+	errUpstream := errors.New("upstream is down")
+
+	// closed: failures below the threshold keep the breaker closed
+	assert.NoError(t, breaker.Allow())
+	breaker.Failure(errUpstream)
+	assert.Equal(t, StateClosed, breaker.Snapshot().State)
+
+	// a success resets the consecutive failures
+	assert.NoError(t, breaker.Allow())
+	breaker.Success()
+	assert.Equal(t, 0, breaker.Snapshot().ConsecutiveFailures)
+
+	// reaching the threshold opens the breaker
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, breaker.Allow())
+		breaker.Failure(errUpstream)
+	}
+	snapshot := breaker.Snapshot()
+	assert.Equal(t, StateOpen, snapshot.State)
+	assert.Equal(t, "upstream is down", snapshot.LastError)
+	assert.Equal(t, now, snapshot.OpenedAt)
+	assert.ErrorIs(t, breaker.Allow(), ErrOpen)
+
+	// after the timeout a single probe is let through
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, StateHalfOpen, breaker.Snapshot().State)
+	assert.NoError(t, breaker.Allow())
+	assert.ErrorIs(t, breaker.Allow(), ErrOpen)
+
+	// a failing probe opens the breaker again
+	breaker.Failure(errUpstream)
+	assert.Equal(t, StateOpen, breaker.Snapshot().State)
+	assert.ErrorIs(t, breaker.Allow(), ErrOpen)
+
+	// a cancelled probe frees its slot, a successful probe closes the breaker
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, breaker.Allow())
+	breaker.Cancel()
+	assert.NoError(t, breaker.Allow())
+	breaker.Success()
+	assert.Equal(t, StateClosed, breaker.Snapshot().State)
+	assert.NoError(t, breaker.Allow())
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	assert.Same(t, registry.Get("b"), registry.Get("b"))
+	registry.Get("a").Failure(errors.New("failed"))
+
+	snapshots := registry.Snapshot()
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, "a", snapshots[0].Name)
+	assert.Equal(t, StateOpen, snapshots[0].State)
+	assert.Equal(t, "b", snapshots[1].Name)
+	assert.Equal(t, StateClosed, snapshots[1].State)
+}
+
+func TestRegistryPrunesIdleBreakers(t *testing.T) {
+	now := time.Date(2023, 7, 25, 12, 0, 0, 0, time.UTC)
+	registry := NewRegistry(Config{FailureThreshold: 1, OpenTimeout: time.Hour})
+	registry.now = func() time.Time { return now }
+	registry.lastPrune = now
+
+	idle := registry.Get("http://idle.example.com/rss.xml")
+	open := registry.Get("http://open.example.com/rss.xml")
+	open.Failure(errors.New("failed"))
+	used := registry.Get("http://used.example.com/rss.xml")
+
+	now = now.Add(idleBreakerTTL / 2)
+	assert.NoError(t, used.Allow())
+	used.Success()
+
+	// the idle breaker is forgotten, the open one still rejects calls and is kept
+	now = now.Add(idleBreakerTTL/2 + time.Second)
+	assert.NotSame(t, idle, registry.Get("http://idle.example.com/rss.xml"))
+	assert.Same(t, open, registry.Get("http://open.example.com/rss.xml"))
+	assert.Same(t, used, registry.Get("http://used.example.com/rss.xml"))
+	assert.Len(t, registry.Snapshot(), 3)
+
+	// once its timeout elapsed the open breaker goes as well
+	now = now.Add(time.Hour)
+	registry.Get("http://other.example.com/rss.xml")
+	assert.Len(t, registry.Snapshot(), 1)
+}
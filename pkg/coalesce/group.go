@@ -0,0 +1,42 @@
+// Package coalesce lets concurrent callers asking for the same key share a single call,
+// e.g. one upstream fetch when a popular cache entry expired.
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/sync/singleflight"
+)
+
+type Group[T any] struct {
+	group singleflight.Group
+}
+
+// Do calls fn once for all concurrent callers of key and hands its result or error to
+// each of them. Callers stop waiting when their ctx is done, and when the shared call
+// only failed because the caller which started it went away, the others try again.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	for {
+		started := false
+		ch := g.group.DoChan(key, func() (interface{}, error) {
+			started = true
+			return fn(ctx)
+		})
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case result := <-ch:
+			if !started && isContextError(result.Err) && ctx.Err() == nil {
+				continue
+			}
+			value, _ := result.Val.(T)
+			return value, result.Err
+		}
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,104 @@
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("ConcurrentCallsShareOneCall", func(t *testing.T) {
+		var group Group[string]
+		var calls int32
+		release := make(chan struct{})
+
+		const callers = 10
+		var wg sync.WaitGroup
+		results := make([]string, callers)
+		errs := make([]error, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = group.Do(context.Background(), "/news", func(ctx context.Context) (string, error) {
+					atomic.AddInt32(&calls, 1)
+					<-release
+					return "news", nil
+				})
+			}(i)
+		}
+
+		// give every caller the chance to join the call before it finishes
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		for i := 0; i < callers; i++ {
+			assert.NoError(t, errs[i])
+			assert.Equal(t, "news", results[i])
+		}
+	})
+
+	t.Run("ErrorIsShared", func(t *testing.T) {
+		var group Group[string]
+		upstreamErr := errors.New("upstream failed")
+		_, err := group.Do(context.Background(), "/news", func(ctx context.Context) (string, error) {
+			return "", upstreamErr
+		})
+		assert.ErrorIs(t, err, upstreamErr)
+	})
+
+	t.Run("WaiterStopsWithItsContext", func(t *testing.T) {
+		var group Group[string]
+		release := make(chan struct{})
+		defer close(release)
+
+		go group.Do(context.Background(), "/news", func(ctx context.Context) (string, error) {
+			<-release
+			return "news", nil
+		})
+		time.Sleep(10 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := group.Do(ctx, "/news", func(ctx context.Context) (string, error) {
+			t.Error("the waiter must not start a second call")
+			return "", nil
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("WaiterRetriesWhenStarterWentAway", func(t *testing.T) {
+		var group Group[string]
+		starterCtx, cancelStarter := context.WithCancel(context.Background())
+		started := make(chan struct{})
+
+		go group.Do(starterCtx, "/news", func(ctx context.Context) (string, error) {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+		<-started
+
+		done := make(chan struct{})
+		var result string
+		var err error
+		go func() {
+			defer close(done)
+			result, err = group.Do(context.Background(), "/news", func(ctx context.Context) (string, error) {
+				return "news", nil
+			})
+		}()
+		time.Sleep(10 * time.Millisecond)
+		cancelStarter()
+		<-done
+
+		assert.NoError(t, err)
+		assert.Equal(t, "news", result)
+	})
+}
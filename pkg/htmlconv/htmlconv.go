@@ -0,0 +1,510 @@
+// Package htmlconv converts article HTML into CommonMark or wrapped plain text.
+package htmlconv
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultWidth is the line width plain text is wrapped at.
+const DefaultWidth = 80
+
+// lineBreak marks a <br> inside inline content until the paragraph is assembled.
+const lineBreak = "\x00"
+
+// ToMarkdown converts the given HTML fragment into CommonMark, relative links and
+// images are resolved against base when it is not nil.
+func ToMarkdown(fragment string, base *url.URL) (string, error) {
+	return convert(fragment, renderer{markdown: true, base: base})
+}
+
+// ToText converts the given HTML fragment into plain text wrapped at width columns,
+// a width <= 0 disables wrapping.
+func ToText(fragment string, width int) (string, error) {
+	return convert(fragment, renderer{width: width})
+}
+
+func convert(fragment string, r renderer) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+
+	return strings.Join(r.blocks(root), "\n\n"), nil
+}
+
+type renderer struct {
+	markdown bool
+	base     *url.URL
+	width    int
+}
+
+// skipped elements never carry readable article text.
+var skipped = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Iframe:   true,
+	atom.Svg:      true,
+	atom.Form:     true,
+	atom.Button:   true,
+	atom.Nav:      true,
+	atom.Aside:    true,
+	atom.Footer:   true,
+	atom.Template: true,
+}
+
+// containers are block elements whose children are rendered as blocks of their own.
+var containers = map[atom.Atom]bool{
+	atom.Div:     true,
+	atom.Section: true,
+	atom.Article: true,
+	atom.Header:  true,
+	atom.Main:    true,
+	atom.Figure:  true,
+	atom.Body:    true,
+	atom.Details: true,
+	atom.Dl:      true,
+	atom.Table:   true,
+	atom.Thead:   true,
+	atom.Tbody:   true,
+	atom.Tfoot:   true,
+	atom.Address: true,
+}
+
+// blocks renders the children of n as a list of blocks which are separated by blank lines.
+func (r renderer) blocks(n *html.Node) []string {
+	var result []string
+	var paragraph strings.Builder
+
+	flush := func() {
+		if text := r.paragraph(paragraph.String()); text != "" {
+			result = append(result, text)
+		}
+		paragraph.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && skipped[c.DataAtom] {
+			continue
+		}
+		if c.Type != html.ElementNode || !isBlock(c.DataAtom) {
+			paragraph.WriteString(r.inline(c))
+			continue
+		}
+
+		flush()
+		result = append(result, r.block(c)...)
+	}
+	flush()
+
+	return result
+}
+
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Li, atom.Blockquote, atom.Pre, atom.Hr,
+		atom.Figcaption, atom.Tr, atom.Dt, atom.Dd:
+		return true
+	}
+	return containers[a]
+}
+
+func (r renderer) block(n *html.Node) []string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := r.paragraph(r.inlineChildren(n))
+		if text == "" {
+			return nil
+		}
+		if r.markdown {
+			level, _ := strconv.Atoi(n.Data[1:])
+			return []string{strings.Repeat("#", level) + " " + strings.ReplaceAll(text, "\n", " ")}
+		}
+		return []string{text}
+	case atom.Ul, atom.Ol:
+		if list := r.list(n); list != "" {
+			return []string{list}
+		}
+		return nil
+	case atom.Blockquote:
+		return r.blockquote(n)
+	case atom.Pre:
+		return r.pre(n)
+	case atom.Hr:
+		if r.markdown {
+			return []string{"---"}
+		}
+		return nil
+	case atom.Tr:
+		var cells []string
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.DataAtom == atom.Td || c.DataAtom == atom.Th) {
+				cells = append(cells, collapse(r.inlineChildren(c)))
+			}
+		}
+		if row := strings.Join(cells, " | "); strings.TrimSpace(strings.ReplaceAll(row, "|", "")) != "" {
+			if r.markdown {
+				row = escapeLineStart(row)
+			}
+			return []string{row}
+		}
+		return nil
+	}
+	// paragraphs, list items and containers may hold nested blocks
+	return r.blocks(n)
+}
+
+func (r renderer) list(n *html.Node) string {
+	var items []string
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		number = start
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		inner := r.indented(len(marker)).blocks(c)
+		if len(inner) == 0 {
+			continue
+		}
+		items = append(items, prefixLines(strings.Join(inner, "\n"), marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+func (r renderer) blockquote(n *html.Node) []string {
+	prefix := "> "
+	if !r.markdown {
+		prefix = "    "
+	}
+
+	inner := r.indented(len(prefix)).blocks(n)
+	if len(inner) == 0 {
+		return nil
+	}
+
+	return []string{prefixLines(strings.Join(inner, "\n\n"), prefix, prefix)}
+}
+
+func (r renderer) pre(n *html.Node) []string {
+	text := strings.Trim(textContent(n), "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if !r.markdown {
+		return []string{text}
+	}
+
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return []string{fence + "\n" + text + "\n" + fence}
+}
+
+// indented returns a renderer for content nested behind a prefix of the given width.
+func (r renderer) indented(indent int) renderer {
+	if r.width > 0 {
+		r.width -= indent
+		if r.width < 20 {
+			r.width = 20
+		}
+	}
+	return r
+}
+
+// paragraph turns collected inline content into its final form, collapsing
+// whitespace and wrapping plain text.
+func (r renderer) paragraph(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, lineBreak) {
+		line = collapse(line)
+		if line == "" {
+			continue
+		}
+		if r.markdown {
+			line = escapeLineStart(line)
+		} else {
+			line = wrap(line, r.width)
+		}
+		lines = append(lines, line)
+	}
+
+	if r.markdown {
+		// two trailing spaces is the CommonMark hard line break
+		return strings.Join(lines, "  \n")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r renderer) inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(r.inline(c))
+	}
+	return b.String()
+}
+
+func (r renderer) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		if r.markdown {
+			return escapeMarkdown(n.Data)
+		}
+		return n.Data
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	if skipped[n.DataAtom] {
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return lineBreak
+	case atom.Img:
+		if !r.markdown {
+			return ""
+		}
+		src := r.destination(attr(n, "src"))
+		if src == "" {
+			return ""
+		}
+		return "![" + escapeMarkdown(collapse(attr(n, "alt"))) + "](" + linkDestination(src) + ")"
+	}
+
+	content := r.inlineChildren(n)
+	if !r.markdown || strings.TrimSpace(content) == "" {
+		return content
+	}
+
+	switch n.DataAtom {
+	case atom.A:
+		href := r.destination(attr(n, "href"))
+		if href == "" || strings.HasPrefix(href, "#") {
+			return content
+		}
+		return "[" + strings.TrimSpace(content) + "](" + linkDestination(href) + ")"
+	case atom.Strong, atom.B:
+		return wrapEmphasis(content, "**")
+	case atom.Em, atom.I:
+		return wrapEmphasis(content, "*")
+	case atom.Code, atom.Kbd, atom.Samp:
+		return codeSpan(strings.TrimSpace(textContent(n)))
+	}
+	return content
+}
+
+// allowedSchemes are the schemes links and images may use, e.g. javascript: or data:
+// destinations are dropped. Relative references are resolved against the base if any.
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// destination resolves a link or image reference, it returns an empty string for
+// references which can not be parsed or use a scheme which is not allowed.
+func (r renderer) destination(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if r.base != nil {
+		u = r.base.ResolveReference(u)
+	}
+	if u.Scheme != "" && !allowedSchemes[strings.ToLower(u.Scheme)] {
+		return ""
+	}
+	if r.base == nil {
+		return ref
+	}
+	return u.String()
+}
+
+// codeSpan fences code with a backtick string longer than any inside it, as CommonMark
+// specifies, code starting or ending with a backtick is padded with a space.
+func codeSpan(code string) string {
+	longest, run := 0, 0
+	for _, c := range code {
+		if c != '`' {
+			run = 0
+			continue
+		}
+		run++
+		if run > longest {
+			longest = run
+		}
+	}
+
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+// wrapEmphasis keeps surrounding whitespace outside of the markers, CommonMark
+// does not treat `** bold **` as emphasis.
+func wrapEmphasis(content, marker string) string {
+	trimmed := strings.TrimSpace(content)
+	leading := content[:strings.Index(content, trimmed)]
+	trailing := content[len(leading)+len(trimmed):]
+	return leading + marker + trimmed + marker + trailing
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	// raw HTML would be passed through by CommonMark renderers
+	`<`, `\<`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// EscapeMarkdown escapes text so CommonMark renders it as it is, including characters
+// which only start a heading, quote, list or thematic break at the beginning of a line.
+func EscapeMarkdown(text string) string {
+	lines := strings.Split(escapeMarkdown(text), "\n")
+	for i, line := range lines {
+		lines[i] = escapeLineStart(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// orderedListMarker matches the start of an ordered list item, e.g. `1.` or `2)`.
+var orderedListMarker = regexp.MustCompile(`^\d{1,9}[.)]`)
+
+// escapeLineStart escapes the block markers text at the start of a line would be read as.
+func escapeLineStart(line string) string {
+	text := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(text)]
+	if text == "" {
+		return line
+	}
+	if strings.ContainsRune("#>-+=~", rune(text[0])) {
+		return indent + `\` + text
+	}
+	if marker := orderedListMarker.FindString(text); marker != "" {
+		return indent + marker[:len(marker)-1] + `\` + text[len(marker)-1:]
+	}
+	return line
+}
+
+// linkDestination wraps destinations which would end the link early, e.g. at a space
+// or a closing parenthesis, in angle brackets.
+func linkDestination(dest string) string {
+	if !strings.ContainsAny(dest, " ()<>\\") {
+		return dest
+	}
+	return "<" + destinationEscaper.Replace(dest) + ">"
+}
+
+var destinationEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`<`, `\<`,
+	`>`, `\>`,
+	"\n", "%0A",
+)
+
+// prefixLines puts first in front of the first line and rest in front of every other non-empty line.
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line != "":
+			lines[i] = rest + line
+		default:
+			lines[i] = strings.TrimRight(rest, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap breaks a single line of text into lines no longer than width, words longer
+// than width are kept on their own line.
+func wrap(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	lineLen := 0
+	for _, word := range strings.Fields(s) {
+		switch {
+		case lineLen == 0:
+		case lineLen+1+utf8.RuneCountInString(word) > width:
+			b.WriteString("\n")
+			lineLen = 0
+		default:
+			b.WriteString(" ")
+			lineLen++
+		}
+		b.WriteString(word)
+		lineLen += utf8.RuneCountInString(word)
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package htmlconv
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+const articleHTML = `
+<h2>Heading</h2>
+<p>First <strong>bold</strong> and <em>italic</em> text with a <a href="/more">link</a>.</p>
+<script>alert("ignored")</script>
+<ul>
+	<li>One</li>
+	<li>Two
+		<ol><li>Nested</li></ol>
+	</li>
+</ul>
+<blockquote><p>Quoted line</p><p>Second quote</p></blockquote>
+<figure><img src="/hero.jpg" alt="Hero"><figcaption>Caption</figcaption></figure>
+<p>Line one<br>Line two with snake_case</p>
+`
+
+func TestToMarkdown(t *testing.T) {
+	base, err := url.Parse("https://www.example.com/news/article")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	markdown, err := ToMarkdown(articleHTML, base)
+	assert.NoError(t, err)
+	assert.Equal(t, "## Heading\n\n"+
+		"First **bold** and *italic* text with a [link](https://www.example.com/more).\n\n"+
+		"- One\n"+
+		"- Two\n"+
+		"  1. Nested\n\n"+
+		"> Quoted line\n"+
+		">\n"+
+		"> Second quote\n\n"+
+		"![Hero](https://www.example.com/hero.jpg)\n\n"+
+		"Caption\n\n"+
+		"Line one  \nLine two with snake\\_case", markdown)
+}
+
+func TestToText(t *testing.T) {
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name     string
+		html     string
+		width    int
+		expected string
+	}{
+		{
+			name:  "Structure",
+			html:  articleHTML,
+			width: DefaultWidth,
+			expected: "Heading\n\n" +
+				"First bold and italic text with a link.\n\n" +
+				"- One\n" +
+				"- Two\n" +
+				"  1. Nested\n\n" +
+				"    Quoted line\n" +
+				"\n" +
+				"    Second quote\n\n" +
+				"Caption\n\n" +
+				"Line one\nLine two with snake_case",
+		},
+		{
+			name:     "Wrap",
+			html:     "<p>The quick brown fox jumps over the lazy dog</p>",
+			width:    20,
+			expected: "The quick brown fox\njumps over the lazy\ndog",
+		},
+		{
+			name:     "NoWrap",
+			html:     "<p>The quick brown fox jumps over the lazy dog</p>",
+			width:    0,
+			expected: "The quick brown fox jumps over the lazy dog",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			text, err := ToText(tc.html, tc.width)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestToMarkdownEscaping(t *testing.T) {
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "Heading",
+			html:     "<p># not a heading</p>",
+			expected: `\# not a heading`,
+		},
+		{
+			name:     "Quote",
+			html:     "<p>&gt; not a quote</p>",
+			expected: `\> not a quote`,
+		},
+		{
+			name:     "BulletList",
+			html:     "<p>- not a list<br>+ neither</p>",
+			expected: "\\- not a list  \n\\+ neither",
+		},
+		{
+			name:     "OrderedList",
+			html:     "<p>1. not a list</p><p>2020) a year</p>",
+			expected: "1\\. not a list\n\n2020\\) a year",
+		},
+		{
+			name:     "ThematicBreak",
+			html:     "<p>Title<br>===</p>",
+			expected: "Title  \n\\===",
+		},
+		{
+			name:     "RawHTML",
+			html:     "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+			expected: `\<script>alert(1)\</script>`,
+		},
+		{
+			name:     "LinkDestination",
+			html:     `<p><a href="https://en.wikipedia.org/wiki/Go_(programming_language)">Go</a></p>`,
+			expected: "[Go](<https://en.wikipedia.org/wiki/Go_(programming_language)>)",
+		},
+		{
+			name:     "ImageDestination",
+			html:     `<img src="/images/my photo.jpg" alt="Photo">`,
+			expected: "![Photo](</images/my photo.jpg>)",
+		},
+		{
+			name:     "UnsafeSchemes",
+			html:     `<p><a href="JavaScript:alert(1)">script</a> <a href="vbscript:msgbox">vb</a> <img src="data:image/png;base64,AAAA" alt="data"><a href="mailto:news@example.com">mail</a></p>`,
+			expected: "script vb [mail](mailto:news@example.com)",
+		},
+		{
+			name:     "CodeWithBackticks",
+			html:     "<p>Run <code>echo `date`</code> or <code>``</code></p>",
+			expected: "Run `` echo `date` `` or ``` `` ```",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			markdown, err := ToMarkdown(tc.html, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, markdown)
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	assert.Equal(t, "\\# 10 \\*best\\* \\[tips\\]\n1\\. first", EscapeMarkdown("# 10 *best* [tips]\n1. first"))
+}
@@ -0,0 +1,222 @@
+// Package httpclient builds the HTTP client shared by every outbound request of the server.
+package httpclient
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"github.com/andybalholm/brotli"
+	"github.com/fir1/news/pkg/netguard"
+	"github.com/fir1/news/pkg/ratelimit"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrResponseTooLarge is returned while reading a response body larger than Config.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+type Config struct {
+	// Timeout limits the whole exchange including reading the body, zero means no limit
+	Timeout               time.Duration
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	// MaxConnsPerHost limits dialing, active and idle connections per host, zero means no limit
+	MaxConnsPerHost int
+	// MaxResponseBytes limits the decoded size of response bodies, zero means no limit
+	MaxResponseBytes int64
+	UserAgent        string
+	// RequestsPerSecondPerHost and BurstPerHost configure the token bucket of every upstream
+	// host, MaxConcurrentPerHost caps the requests in flight per host, zero means no limit
+	RequestsPerSecondPerHost float64
+	BurstPerHost             int
+	MaxConcurrentPerHost     int
+}
+
+// New creates a pooled client which sets a User-Agent, negotiates gzip, deflate and
+// brotli compression, limits the size of response bodies and the request rate per
+// upstream host, every connection is checked by guard when it is not nil.
+func New(cnf Config, guard *netguard.Guard) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   cnf.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	if guard != nil {
+		// a proxy would connect to the destination on our behalf and bypass the guard
+		transport.Proxy = nil
+		transport.DialContext = guard.DialFunc(dialer)
+	}
+	transport.TLSHandshakeTimeout = cnf.TLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = cnf.ResponseHeaderTimeout
+	transport.IdleConnTimeout = cnf.IdleConnTimeout
+	transport.MaxIdleConns = cnf.MaxIdleConns
+	transport.MaxIdleConnsPerHost = cnf.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = cnf.MaxConnsPerHost
+
+	var next http.RoundTripper = transport
+	if cnf.RequestsPerSecondPerHost > 0 || cnf.MaxConcurrentPerHost > 0 {
+		next = &rateLimitedTransport{
+			next:    transport,
+			limiter: ratelimit.NewHostLimiter(cnf.RequestsPerSecondPerHost, cnf.BurstPerHost, cnf.MaxConcurrentPerHost),
+		}
+	}
+
+	return &http.Client{
+		Transport: &roundTripper{
+			next:             next,
+			userAgent:        cnf.UserAgent,
+			maxResponseBytes: cnf.MaxResponseBytes,
+		},
+		Timeout: cnf.Timeout,
+	}
+}
+
+type roundTripper struct {
+	next             http.RoundTripper
+	userAgent        string
+	maxResponseBytes int64
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	if rt.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", rt.userAgent)
+	}
+
+	// setting Accept-Encoding turns off the transparent gzip handling of http.Transport,
+	// so every encoding offered here is decoded below
+	decode := req.Header.Get("Accept-Encoding") == "" && req.Method != http.MethodHead
+	if decode {
+		req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if decode {
+		err = decodeBody(resp)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	if rt.maxResponseBytes > 0 {
+		if resp.ContentLength > rt.maxResponseBytes {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrResponseTooLarge, resp.ContentLength, rt.maxResponseBytes)
+		}
+		resp.Body = &limitedBody{body: resp.Body, remaining: rt.maxResponseBytes}
+	}
+	return resp, nil
+}
+
+func decodeBody(resp *http.Response) error {
+	var reader io.Reader
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		switch {
+		case errors.Is(err, io.EOF):
+			// empty body, e.g. a 304 or 204 response with the header set
+			reader = strings.NewReader("")
+		case err != nil:
+			return err
+		default:
+			reader = gz
+		}
+	case "deflate":
+		var err error
+		reader, err = deflateReader(resp.Body)
+		if err != nil {
+			return err
+		}
+	case "br":
+		reader = brotli.NewReader(resp.Body)
+	default:
+		// unknown encodings are passed through untouched
+		return nil
+	}
+
+	resp.Body = &decodedBody{Reader: reader, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// deflateReader decodes the zlib stream HTTP deflate is (RFC 9110), and the raw deflate
+// stream some servers send instead.
+func deflateReader(body io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(body)
+	header, err := buffered.Peek(2)
+	switch {
+	case len(header) == 0 && errors.Is(err, io.EOF):
+		return strings.NewReader(""), nil
+	case len(header) < 2:
+		return flate.NewReader(buffered), nil
+	}
+
+	// a zlib header names the deflate method and is a multiple of 31
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
+
+type decodedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b *decodedBody) Close() error {
+	if closer, ok := b.Reader.(io.Closer); ok {
+		closer.Close()
+	}
+	return b.body.Close()
+}
+
+// limitedBody fails with ErrResponseTooLarge instead of silently truncating the body
+// the way io.LimitReader does, a truncated feed or article would look valid.
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// read one byte more than allowed to find out whether the body is too large
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), ErrResponseTooLarge
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,145 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	body := strings.Repeat("<item>news</item>", 100)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-User-Agent", r.Header.Get("User-Agent"))
+
+		var buf bytes.Buffer
+		switch r.URL.Path {
+		case "/gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(&buf)
+			gz.Write([]byte(body))
+			gz.Close()
+		case "/deflate":
+			// HTTP deflate is a zlib stream
+			w.Header().Set("Content-Encoding", "deflate")
+			zw := zlib.NewWriter(&buf)
+			zw.Write([]byte(body))
+			zw.Close()
+		case "/raw-deflate":
+			w.Header().Set("Content-Encoding", "deflate")
+			fw, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+			fw.Write([]byte(body))
+			fw.Close()
+		case "/br":
+			w.Header().Set("Content-Encoding", "br")
+			br := brotli.NewWriter(&buf)
+			br.Write([]byte(body))
+			br.Close()
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		default:
+			buf.WriteString(body)
+		}
+		w.Write(buf.Bytes())
+	}))
+	defer ts.Close()
+
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name             string
+		path             string
+		maxResponseBytes int64
+		expectedErr      error
+	}{
+		{
+			name: "Plain",
+			path: "/plain",
+		},
+		{
+			name: "Gzip",
+			path: "/gzip",
+		},
+		{
+			name: "Deflate",
+			path: "/deflate",
+		},
+		{
+			name: "RawDeflate",
+			path: "/raw-deflate",
+		},
+		{
+			name: "Brotli",
+			path: "/br",
+		},
+		{
+			name:             "TooLarge",
+			path:             "/plain",
+			maxResponseBytes: int64(len(body) - 1),
+			expectedErr:      ErrResponseTooLarge,
+		},
+		{
+			// the limit applies to the decoded body, a small compressed response can still be too large
+			name:             "DecodedTooLarge",
+			path:             "/gzip",
+			maxResponseBytes: int64(len(body) - 1),
+			expectedErr:      ErrResponseTooLarge,
+		},
+		{
+			name:             "ExactlyAtLimit",
+			path:             "/br",
+			maxResponseBytes: int64(len(body)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := New(Config{UserAgent: "test-agent", MaxResponseBytes: tc.maxResponseBytes}, nil)
+
+			resp, err := client.Get(ts.URL + tc.path)
+			if err == nil {
+				defer resp.Body.Close()
+				var data []byte
+				data, err = io.ReadAll(resp.Body)
+				if err == nil {
+					assert.Equal(t, body, string(data))
+					assert.Equal(t, "test-agent", resp.Header.Get("X-User-Agent"))
+				}
+			}
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/slow", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		start := time.Now()
+		_, err = New(Config{}, nil).Do(request)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,40 @@
+package httpclient
+
+import (
+	"github.com/fir1/news/pkg/ratelimit"
+	"io"
+	"net/http"
+)
+
+// rateLimitedTransport waits for the turn of the upstream host before sending a request,
+// the concurrency slot is held until the response body is closed.
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter *ratelimit.HostLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.Acquire(req.Context(), req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,209 @@
+// Package netguard protects outbound HTTP requests made on behalf of users from reaching
+// loopback, private, link-local and other internal addresses (SSRF).
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrForbiddenDestination is returned when a URL or an address it resolves to is refused.
+var ErrForbiddenDestination = errors.New("destination is not allowed")
+
+// internalNetworks are ranges which are not covered by the net.IP helpers but must not be reachable either.
+var internalNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this" network
+	"100.64.0.0/10", // carrier-grade NAT shared address space
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"64:ff9b::/96",  // NAT64, may embed an internal IPv4 address
+)
+
+// Guard decides which hosts may be fetched. Hosts and addresses in the allow list are
+// always reachable, those in the deny list never are, everything else is reachable as
+// long as it resolves to public addresses only.
+type Guard struct {
+	allowedHosts []string
+	allowedNets  []*net.IPNet
+	deniedHosts  []string
+	deniedNets   []*net.IPNet
+	resolver     *net.Resolver
+	dialer       *net.Dialer
+}
+
+// New creates a Guard, allowed and denied entries are host names (`example.com`),
+// wildcard domains (`*.example.com`), IP addresses or CIDR ranges.
+func New(allowed, denied []string) (*Guard, error) {
+	g := &Guard{
+		resolver: net.DefaultResolver,
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+	}
+
+	var err error
+	g.allowedHosts, g.allowedNets, err = parseEntries(allowed)
+	if err != nil {
+		return nil, fmt.Errorf("allowed hosts: %w", err)
+	}
+	g.deniedHosts, g.deniedNets, err = parseEntries(denied)
+	if err != nil {
+		return nil, fmt.Errorf("denied hosts: %w", err)
+	}
+	return g, nil
+}
+
+// CheckURL validates the scheme of the URL and resolves its host to make sure it is
+// allowed, it lets callers refuse a request before any connection is attempted.
+func (g *Guard) CheckURL(ctx context.Context, u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q is not supported", ErrForbiddenDestination, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: url has no host", ErrForbiddenDestination)
+	}
+	_, err := g.resolve(ctx, u.Hostname())
+	return err
+}
+
+// DialContext is meant to be used as http.Transport.DialContext, it checks every
+// connection including the ones made while following redirects.
+func (g *Guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return g.DialFunc(g.dialer)(ctx, network, address)
+}
+
+// DialFunc returns a DialContext function which opens the checked connections with dialer.
+func (g *Guard) DialFunc(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		ips, err := g.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		// dial the checked addresses only, dialing the host name again would resolve it a
+		// second time and could connect to a different (internal) address
+		var dialErr error
+		for _, ip := range ips {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = err
+		}
+		return nil, dialErr
+	}
+}
+
+// resolve returns the addresses of host once it and all of its addresses passed the checks.
+func (g *Guard) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if matchHost(g.deniedHosts, host) {
+		return nil, fmt.Errorf("%w: host %s is denied", ErrForbiddenDestination, host)
+	}
+	hostAllowed := matchHost(g.allowedHosts, host)
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := g.resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if matchIP(g.deniedNets, ip) {
+			return nil, fmt.Errorf("%w: %s resolves to denied address %s", ErrForbiddenDestination, host, ip)
+		}
+		if !hostAllowed && !matchIP(g.allowedNets, ip) && isInternal(ip) {
+			return nil, fmt.Errorf("%w: %s resolves to internal address %s", ErrForbiddenDestination, host, ip)
+		}
+	}
+	return ips, nil
+}
+
+func isInternal(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		matchIP(internalNetworks, ip)
+}
+
+func matchHost(hosts []string, host string) bool {
+	for _, h := range hosts {
+		if h == host || (strings.HasPrefix(h, "*.") && strings.HasSuffix(host, h[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseEntries(entries []string) ([]string, []*net.IPNet, error) {
+	var hosts []string
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+		case strings.Contains(entry, "/"):
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, nil, err
+			}
+			networks = append(networks, network)
+		case net.ParseIP(entry) != nil:
+			ip := net.ParseIP(entry)
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		default:
+			hosts = append(hosts, strings.TrimSuffix(entry, "."))
+		}
+	}
+	return hosts, networks, nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
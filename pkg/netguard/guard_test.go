@@ -0,0 +1,132 @@
+package netguard
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newClient(t *testing.T, allowed, denied []string) *http.Client {
+	guard, err := New(allowed, denied)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = guard.DialContext
+	return &http.Client{Transport: transport}
+}
+
+func TestGuardDial(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			// redirect to the same server using its IP address instead of the allowed host name
+			http.Redirect(w, r, "http://"+r.Context().Value(http.LocalAddrContextKey).(net.Addr).String()+"/", http.StatusFound)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	localhostURL := "http://localhost:" + u.Port()
+
+	// Create test cases using table-driven testing
+	testCases := []struct {
+		name      string
+		allowed   []string
+		denied    []string
+		url       string
+		forbidden bool
+	}{
+		{
+			name:      "LoopbackBlocked",
+			url:       ts.URL,
+			forbidden: true,
+		},
+		{
+			name:    "AllowedCIDR",
+			allowed: []string{"127.0.0.0/8"},
+			url:     ts.URL,
+		},
+		{
+			name:    "AllowedHost",
+			allowed: []string{"localhost"},
+			url:     localhostURL,
+		},
+		{
+			name:      "DeniedWinsOverAllowed",
+			allowed:   []string{"localhost"},
+			denied:    []string{"127.0.0.1"},
+			url:       localhostURL,
+			forbidden: true,
+		},
+		{
+			name:      "RedirectToInternalAddressBlocked",
+			allowed:   []string{"localhost"},
+			url:       localhostURL + "/redirect",
+			forbidden: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := newClient(t, tc.allowed, tc.denied).Get(tc.url)
+			if tc.forbidden {
+				assert.ErrorIs(t, err, ErrForbiddenDestination)
+				return
+			}
+
+			assert.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestGuardCheckURL(t *testing.T) {
+	guard, err := New(nil, []string{"*.internal.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		url       string
+		forbidden bool
+	}{
+		{url: "http://127.0.0.1/rss.xml", forbidden: true},
+		{url: "http://[::1]/rss.xml", forbidden: true},
+		{url: "http://169.254.169.254/latest/meta-data", forbidden: true},
+		{url: "http://10.0.0.1/rss.xml", forbidden: true},
+		{url: "http://192.168.1.1/rss.xml", forbidden: true},
+		{url: "http://[::ffff:127.0.0.1]/rss.xml", forbidden: true},
+		{url: "http://0.0.0.0/rss.xml", forbidden: true},
+		{url: "http://feeds.internal.example.com/rss.xml", forbidden: true},
+		{url: "file:///etc/passwd", forbidden: true},
+		{url: "http://93.184.216.34/rss.xml"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = guard.CheckURL(context.Background(), u)
+			if tc.forbidden {
+				assert.ErrorIs(t, err, ErrForbiddenDestination)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Package ratelimit keeps outbound requests polite: every upstream host gets its own
+// token bucket and a cap of concurrent requests.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/time/rate"
+	"sync"
+	"time"
+)
+
+// ErrDeadlineExceeded is returned when a request would have to wait for its turn past
+// the deadline of its context.
+var ErrDeadlineExceeded = errors.New("upstream rate limit would exceed the request deadline")
+
+// idleHostTTL is how long the state of a host without requests is kept.
+const idleHostTTL = 10 * time.Minute
+
+type HostLimiter struct {
+	limit         rate.Limit
+	burst         int
+	maxConcurrent int
+
+	mu        sync.Mutex
+	hosts     map[string]*host
+	lastPrune time.Time
+}
+
+type host struct {
+	limiter  *rate.Limiter
+	slots    chan struct{}
+	inFlight int
+	lastUsed time.Time
+}
+
+// NewHostLimiter allows requestsPerSecond with bursts of burst requests and at most
+// maxConcurrent requests in flight per host, a value <= 0 disables that limit.
+func NewHostLimiter(requestsPerSecond float64, burst, maxConcurrent int) *HostLimiter {
+	limit := rate.Inf
+	if requestsPerSecond > 0 {
+		limit = rate.Limit(requestsPerSecond)
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &HostLimiter{
+		limit:         limit,
+		burst:         burst,
+		maxConcurrent: maxConcurrent,
+		hosts:         make(map[string]*host),
+		lastPrune:     time.Now(),
+	}
+}
+
+// Acquire blocks until a request to hostName may start, queued callers give up when ctx
+// is done or when their turn would come after the deadline of ctx. The returned release
+// function must be called once the request finished.
+func (l *HostLimiter) Acquire(ctx context.Context, hostName string) (func(), error) {
+	h := l.host(hostName)
+
+	release := func() {
+		l.mu.Lock()
+		h.inFlight--
+		h.lastUsed = time.Now()
+		l.mu.Unlock()
+	}
+
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+		releaseHost := release
+		release = func() {
+			<-h.slots
+			releaseHost()
+		}
+	}
+
+	err := h.limiter.Wait(ctx)
+	if err != nil {
+		release()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrDeadlineExceeded, hostName)
+	}
+
+	var once sync.Once
+	return func() { once.Do(release) }, nil
+}
+
+func (l *HostLimiter) host(hostName string) *host {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) > idleHostTTL {
+		l.prune(now)
+	}
+
+	h, ok := l.hosts[hostName]
+	if !ok {
+		h = &host{limiter: rate.NewLimiter(l.limit, l.burst)}
+		if l.maxConcurrent > 0 {
+			h.slots = make(chan struct{}, l.maxConcurrent)
+		}
+		l.hosts[hostName] = h
+	}
+	h.inFlight++
+	h.lastUsed = now
+	return h
+}
+
+// prune forgets hosts which have been idle long enough for their bucket to be full again,
+// articles are fetched from arbitrary hosts and the map would grow forever otherwise.
+func (l *HostLimiter) prune(now time.Time) {
+	for name, h := range l.hosts {
+		if h.inFlight == 0 && now.Sub(h.lastUsed) > idleHostTTL {
+			delete(l.hosts, name)
+		}
+	}
+	l.lastPrune = now
+}
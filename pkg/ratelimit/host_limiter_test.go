@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHostLimiterRate(t *testing.T) {
+	limiter := NewHostLimiter(20, 2, 0)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		release, err := limiter.Acquire(ctx, "feeds.bbci.co.uk")
+		assert.NoError(t, err)
+		release()
+	}
+	// the burst of 2 passes immediately, the other 2 requests wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// other hosts have their own bucket
+	start = time.Now()
+	release, err := limiter.Acquire(ctx, "feeds.skynews.com")
+	assert.NoError(t, err)
+	release()
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestHostLimiterDeadline(t *testing.T) {
+	limiter := NewHostLimiter(1, 1, 0)
+
+	release, err := limiter.Acquire(context.Background(), "example.com")
+	assert.NoError(t, err)
+	release()
+
+	// the next token is one second away, the caller can not wait that long
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = limiter.Acquire(ctx, "example.com")
+	assert.ErrorIs(t, err, ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestHostLimiterConcurrency(t *testing.T) {
+	limiter := NewHostLimiter(0, 0, 2)
+
+	var inFlight, maxInFlight int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := limiter.Acquire(context.Background(), "example.com")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer release()
+
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), maxInFlight)
+
+	// a queued caller gives up with its context
+	release, _ := limiter.Acquire(context.Background(), "example.com")
+	defer release()
+	release2, _ := limiter.Acquire(context.Background(), "example.com")
+	defer release2()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := limiter.Acquire(ctx, "example.com")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
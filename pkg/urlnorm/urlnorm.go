@@ -0,0 +1,44 @@
+// Package urlnorm normalises URLs so equivalent spellings of a URL compare equal,
+// e.g. when they are used as cache keys.
+package urlnorm
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Canonical lowercases the scheme and host, drops default ports and the fragment and
+// sorts the query, URLs which can not be parsed are returned unchanged. A parameter
+// without a value is written with an empty one, `?foo` and `?foo=` are the same URL.
+func Canonical(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		// IPv6 literal
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	// Encode sorts the parameters by key and keeps the order of repeated keys
+	u.RawQuery = u.Query().Encode()
+	return u.String()
+}
@@ -0,0 +1,30 @@
+package urlnorm
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "https://www.bbc.co.uk/news/article", expected: "https://www.bbc.co.uk/news/article"},
+		{input: "HTTPS://WWW.BBC.CO.UK:443/news/article#comments", expected: "https://www.bbc.co.uk/news/article"},
+		{input: "http://example.com", expected: "http://example.com/"},
+		{input: "http://example.com:8080/a?b=2&a=1&b=1", expected: "http://example.com:8080/a?a=1&b=2&b=1"},
+		{input: "http://[::1]:80/a", expected: "http://[::1]/a"},
+		// parameters without a value are deliberately merged with empty ones
+		{input: "http://example.com/feed.xml?foo", expected: "http://example.com/feed.xml?foo="},
+		{input: "http://example.com/feed.xml?foo=", expected: "http://example.com/feed.xml?foo="},
+		{input: " https://example.com/a ", expected: "https://example.com/a"},
+		{input: "not a url", expected: "not a url"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Canonical(tc.input))
+		})
+	}
+}